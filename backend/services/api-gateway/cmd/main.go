@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -15,7 +16,6 @@ import (
 	"baribhara/api-gateway/pkg/logger"
 
 	"github.com/gin-gonic/gin"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 )
 
@@ -29,6 +29,10 @@ func main() {
 
 	// Load configuration
 	cfg, err := config.Load()
+	var invalid *config.ValidationError
+	if errors.As(err, &invalid) {
+		zapLogger.Fatal("Refusing to start with an invalid configuration", zap.Strings("problems", invalid.Problems))
+	}
 	if err != nil {
 		zapLogger.Fatal("Failed to load configuration", zap.Error(err))
 	}
@@ -44,26 +48,17 @@ func main() {
 		zapLogger.Fatal("Failed to initialize gateway", zap.Error(err))
 	}
 
-	// Setup routes
-	router := gw.SetupRoutes()
-
-	// Add Prometheus metrics endpoint
-	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
-
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":    "ok",
-			"timestamp": time.Now().UTC().Format(time.RFC3339),
-			"service":   "api-gateway-go",
-			"version":   "1.0.0",
-		})
-	})
+	// Setup routes; the handler swaps in a new gateway on reload
+	handler, err := gateway.NewHandler(gw, zapLogger)
+	if err != nil {
+		zapLogger.Fatal("Failed to set up routes", zap.Error(err))
+	}
+	defer handler.Close()
 
 	// Create HTTP server
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
-		Handler:      router,
+		Handler:      handler,
 		ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
 		WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
 		IdleTimeout:  time.Duration(cfg.Server.IdleTimeout) * time.Second,
@@ -81,6 +76,34 @@ func main() {
 		}
 	}()
 
+	// Reload configuration on SIGHUP or when the config file changes
+	reload := make(chan struct{}, 1)
+	config.Watch(func() {
+		select {
+		case reload <- struct{}{}:
+		default:
+		}
+	})
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-hup:
+				zapLogger.Info("Received SIGHUP, reloading configuration")
+			case <-reload:
+				zapLogger.Info("Configuration file changed, reloading")
+			}
+
+			newCfg, err := config.Reload()
+			if err != nil {
+				zapLogger.Error("Failed to reload configuration, keeping current configuration", zap.Error(err))
+				continue
+			}
+			handler.Reload(newCfg)
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
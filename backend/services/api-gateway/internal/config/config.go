@@ -2,18 +2,29 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
+// ServicesConfig holds the upstream services by name, as referenced by routes
+type ServicesConfig map[string]ServiceConfig
+
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Services ServicesConfig `mapstructure:"services"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	Metrics  MetricsConfig  `mapstructure:"metrics"`
+	Server        ServerConfig        `mapstructure:"server"`
+	Services      ServicesConfig      `mapstructure:"services"`
+	Redis         RedisConfig         `mapstructure:"redis"`
+	JWT           JWTConfig           `mapstructure:"jwt"`
+	Authorization AuthorizationConfig `mapstructure:"authorization"`
+	Identity      IdentityConfig      `mapstructure:"identity"`
+	RateLimit     RateLimitConfig     `mapstructure:"rate_limit"`
+	Login         LoginConfig         `mapstructure:"login_protection"`
+	Metrics       MetricsConfig       `mapstructure:"metrics"`
+	Headers       HeaderPolicyConfig  `mapstructure:"headers"`
+	Routes        []RouteConfig       `mapstructure:"routes"`
 }
 
 // ServerConfig holds server configuration
@@ -23,26 +34,115 @@ type ServerConfig struct {
 	ReadTimeout  int    `mapstructure:"read_timeout"`
 	WriteTimeout int    `mapstructure:"write_timeout"`
 	IdleTimeout  int    `mapstructure:"idle_timeout"`
-}
 
-// ServicesConfig holds microservices configuration
-type ServicesConfig struct {
-	AuthService      ServiceConfig `mapstructure:"auth_service"`
-	UserService      ServiceConfig `mapstructure:"user_service"`
-	PropertyService  ServiceConfig `mapstructure:"property_service"`
-	TenantService    ServiceConfig `mapstructure:"tenant_service"`
-	InvoiceService   ServiceConfig `mapstructure:"invoice_service"`
-	NotificationService ServiceConfig `mapstructure:"notification_service"`
-	ReportService    ServiceConfig `mapstructure:"report_service"`
-	AdminService     ServiceConfig `mapstructure:"admin_service"`
-	CaretakerService ServiceConfig `mapstructure:"caretaker_service"`
+	// TrustedProxies lists the CIDRs whose X-Forwarded-* headers are kept
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 // ServiceConfig holds individual service configuration
 type ServiceConfig struct {
-	Host string `mapstructure:"host"`
-	Port int    `mapstructure:"port"`
-	GRPCPort int `mapstructure:"grpc_port"`
+	Host           string               `mapstructure:"host"`
+	Port           int                  `mapstructure:"port"`
+	GRPCPort       int                  `mapstructure:"grpc_port"`
+	GRPCPoolSize   int                  `mapstructure:"grpc_pool_size"`
+	GRPCMaxBody    int64                `mapstructure:"grpc_max_body_bytes"`
+	Timeout        time.Duration        `mapstructure:"timeout"`
+	Endpoints      []EndpointConfig     `mapstructure:"endpoints"`
+	Balancer       string               `mapstructure:"balancer"`
+	Transport      TransportConfig      `mapstructure:"transport"`
+	HealthCheck    HealthCheckConfig    `mapstructure:"health_check"`
+	CircuitBreaker CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+	Concurrency    ConcurrencyConfig    `mapstructure:"concurrency"`
+	Retry          RetryConfig          `mapstructure:"retry"`
+	TLS            TLSConfig            `mapstructure:"tls"`
+}
+
+// TLSConfig holds the client TLS settings used to reach a service
+type TLSConfig struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	CAFile             string `mapstructure:"ca_file"`
+	CertFile           string `mapstructure:"cert_file"`
+	KeyFile            string `mapstructure:"key_file"`
+	ServerName         string `mapstructure:"server_name"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+}
+
+// EndpointConfig holds a single upstream instance of a service
+type EndpointConfig struct {
+	Host   string `mapstructure:"host"`
+	Port   int    `mapstructure:"port"`
+	Weight int    `mapstructure:"weight"`
+}
+
+// HealthCheckConfig holds active health checking settings for a service
+type HealthCheckConfig struct {
+	Enabled            bool          `mapstructure:"enabled"`
+	Path               string        `mapstructure:"path"`
+	Interval           time.Duration `mapstructure:"interval"`
+	Timeout            time.Duration `mapstructure:"timeout"`
+	UnhealthyThreshold int           `mapstructure:"unhealthy_threshold"`
+	HealthyThreshold   int           `mapstructure:"healthy_threshold"`
+}
+
+// CircuitBreakerConfig holds circuit breaker thresholds for a service or route
+type CircuitBreakerConfig struct {
+	Enabled             bool          `mapstructure:"enabled"`
+	ConsecutiveFailures int           `mapstructure:"consecutive_failures"`
+	ErrorRateThreshold  float64       `mapstructure:"error_rate_threshold"`
+	MinRequests         int           `mapstructure:"min_requests"`
+	Window              time.Duration `mapstructure:"window"`
+	CoolDown            time.Duration `mapstructure:"cool_down"`
+	HalfOpenRequests    int           `mapstructure:"half_open_requests"`
+}
+
+// ConcurrencyConfig adapts how many requests may be in flight to a service to
+// its latency. Every Window the limit, between MinLimit and MaxLimit, grows
+// while latency stays within Tolerance times its long-run average and shrinks
+// beyond that or on 5xx responses. Low priority routes may only fill
+// LowPriorityShare of the limit, so they are shed first.
+type ConcurrencyConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	InitialLimit     int           `mapstructure:"initial_limit"`
+	MinLimit         int           `mapstructure:"min_limit"`
+	MaxLimit         int           `mapstructure:"max_limit"`
+	Tolerance        float64       `mapstructure:"tolerance"`
+	Window           time.Duration `mapstructure:"window"`
+	LowPriorityShare float64       `mapstructure:"low_priority_share"`
+}
+
+// RetryConfig holds the retry policy and retry budget for a service
+type RetryConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	MaxAttempts      int           `mapstructure:"max_attempts"`
+	InitialBackoff   time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff       time.Duration `mapstructure:"max_backoff"`
+	RetryOn          []int         `mapstructure:"retry_on"`
+	Methods          []string      `mapstructure:"methods"`
+	MaxBodyBytes     int64         `mapstructure:"max_body_bytes"`
+	BudgetRatio      float64       `mapstructure:"budget_ratio"`
+	BudgetMinRetries int           `mapstructure:"budget_min_retries"`
+	BudgetWindow     time.Duration `mapstructure:"budget_window"`
+}
+
+// ResolvedEndpoints returns the configured endpoints, falling back to Host/Port
+// when no endpoint list is given
+func (s ServiceConfig) ResolvedEndpoints() []EndpointConfig {
+	if len(s.Endpoints) > 0 {
+		return s.Endpoints
+	}
+	return []EndpointConfig{{Host: s.Host, Port: s.Port, Weight: 1}}
+}
+
+// TransportConfig holds connection pool settings for an upstream service
+type TransportConfig struct {
+	MaxIdleConns          int           `mapstructure:"max_idle_conns"`
+	MaxIdleConnsPerHost   int           `mapstructure:"max_idle_conns_per_host"`
+	MaxConnsPerHost       int           `mapstructure:"max_conns_per_host"`
+	IdleConnTimeout       time.Duration `mapstructure:"idle_conn_timeout"`
+	KeepAlive             time.Duration `mapstructure:"keep_alive"`
+	DialTimeout           time.Duration `mapstructure:"dial_timeout"`
+	TLSHandshakeTimeout   time.Duration `mapstructure:"tls_handshake_timeout"`
+	ResponseHeaderTimeout time.Duration `mapstructure:"response_header_timeout"`
 }
 
 // RedisConfig holds Redis configuration
@@ -57,6 +157,158 @@ type RedisConfig struct {
 type JWTConfig struct {
 	Secret     string        `mapstructure:"secret"`
 	Expiration time.Duration `mapstructure:"expiration"`
+
+	// Algorithms pins the accepted signing algorithms. Issuer and Audience,
+	// when set, must match the token's iss and aud; RequiredClaims must be
+	// present. Leeway allows for clock skew, and MaxAge rejects tokens issued
+	// longer ago than that regardless of their exp.
+	Algorithms     []string      `mapstructure:"algorithms"`
+	Issuer         string        `mapstructure:"issuer"`
+	Audience       string        `mapstructure:"audience"`
+	RequiredClaims []string      `mapstructure:"required_claims"`
+	Leeway         time.Duration `mapstructure:"leeway"`
+	MaxAge         time.Duration `mapstructure:"max_age"`
+
+	// Keys verifies tokens signed with RS*, PS* and ES* algorithms
+	Keys JWTKeysConfig `mapstructure:"keys"`
+
+	Validation TokenValidationConfig `mapstructure:"validation"`
+	Revocation RevocationConfig      `mapstructure:"revocation"`
+}
+
+// RevocationConfig decides what happens when the token denylist cannot be
+// checked: "open" accepts the token, "closed" rejects the request
+type RevocationConfig struct {
+	FailurePolicy string `mapstructure:"failure_policy"`
+}
+
+// JWTKeysConfig lists where the public keys of asymmetric tokens come from,
+// selected by the token's kid. Keys are reloaded every RefreshInterval; a key
+// that disappears keeps verifying for GracePeriod so its tokens can expire.
+type JWTKeysConfig struct {
+	PEM             []PEMKeyConfig `mapstructure:"pem"`
+	JWKSFile        string         `mapstructure:"jwks_file"`
+	JWKSURL         string         `mapstructure:"jwks_url"`
+	RefreshInterval time.Duration  `mapstructure:"refresh_interval"`
+	GracePeriod     time.Duration  `mapstructure:"grace_period"`
+}
+
+// PEMKeyConfig is a PEM public key or certificate file and its kid
+type PEMKeyConfig struct {
+	KID  string `mapstructure:"kid"`
+	File string `mapstructure:"file"`
+}
+
+// Configured reports whether any key source is set
+func (k JWTKeysConfig) Configured() bool {
+	return len(k.PEM) > 0 || k.JWKSFile != "" || k.JWKSURL != ""
+}
+
+// TokenValidationConfig selects how bearer tokens are validated. In "local"
+// mode the gateway checks the signature itself; in "remote" mode it asks the
+// auth service's AuthService.ValidateToken RPC and caches the verdict for
+// CacheTTL.
+// FailurePolicy decides what happens when the service cannot be reached:
+// "open" falls back to local verification, "closed" rejects the request.
+type TokenValidationConfig struct {
+	Mode          string        `mapstructure:"mode"`
+	Service       string        `mapstructure:"service"`
+	Timeout       time.Duration `mapstructure:"timeout"`
+	CacheTTL      time.Duration `mapstructure:"cache_ttl"`
+	FailurePolicy string        `mapstructure:"failure_policy"`
+}
+
+// AuthorizationConfig holds role-based access settings. A caller's roles are
+// the token's role claim plus the RolesClaim array; RoleHierarchy lists the
+// roles each role includes, e.g. super_admin includes admin.
+type AuthorizationConfig struct {
+	RolesClaim    string              `mapstructure:"roles_claim"`
+	RoleHierarchy map[string][]string `mapstructure:"role_hierarchy"`
+
+	// Ownership names the upstream lookups that route ownership rules use
+	Ownership map[string]OwnershipLookupConfig `mapstructure:"ownership"`
+}
+
+// OwnershipLookupConfig asks a service what a user owns, with a GET of Path or
+// a call to GRPC.Method. ":user_id" and ":resource_id" segments or query values
+// of Path, and the user_id and resource_id entries of GRPC.Params, are filled
+// with the caller's id and the requested resource's id. IDs is the dotted path
+// of the ids the user owns in the JSON response, e.g. "properties.id"; Owner
+// is instead the path of the owner's id when the lookup fetches one resource.
+type OwnershipLookupConfig struct {
+	Service  string           `mapstructure:"service"`
+	Path     string           `mapstructure:"path"`
+	GRPC     *GRPCRouteConfig `mapstructure:"grpc"`
+	IDs      string           `mapstructure:"ids"`
+	Owner    string           `mapstructure:"owner"`
+	CacheTTL time.Duration    `mapstructure:"cache_ttl"`
+}
+
+// IdentityConfig controls the X-User-* headers that tell upstreams who the
+// authenticated caller is. With a SigningSecret the headers are HMAC-signed.
+type IdentityConfig struct {
+	SigningSecret string `mapstructure:"signing_secret"`
+}
+
+// RateLimitConfig holds the rate limit policies. GlobalPolicy, when set,
+// limits every request by IP before auth and routing, so failed logins and
+// unknown paths are limited too. Routes then use DefaultPolicy unless they
+// name another; requests from ExemptCIDRs are never limited.
+// While Redis is unavailable, FailureMode lets requests through ("open"),
+// limits them in memory with each of Replicas gateway replicas enforcing its
+// share of every limit ("local"), or refuses them ("closed").
+type RateLimitConfig struct {
+	GlobalPolicy  string                           `mapstructure:"global_policy"`
+	DefaultPolicy string                           `mapstructure:"default_policy"`
+	ExemptCIDRs   []string                         `mapstructure:"exempt_cidrs"`
+	APIKeyHeader  string                           `mapstructure:"api_key_header"`
+	FailureMode   string                           `mapstructure:"failure_mode"`
+	Replicas      int                              `mapstructure:"replicas"`
+	Policies      map[string]RateLimitPolicyConfig `mapstructure:"policies"`
+}
+
+// RateLimitPolicyConfig allows Requests per Period, in bursts of up to Burst
+// (default Requests), to each caller as identified by Key: any of "ip",
+// "user" and "api_key". Parts the request lacks are left out, and a request
+// with none of them is keyed by IP. Roles overrides the limit for callers
+// holding a role; with several, the most generous applies.
+type RateLimitPolicyConfig struct {
+	Key      []string                       `mapstructure:"key"`
+	Requests int                            `mapstructure:"requests"`
+	Period   time.Duration                  `mapstructure:"period"`
+	Burst    int                            `mapstructure:"burst"`
+	Roles    map[string]RateLimitRoleConfig `mapstructure:"roles"`
+}
+
+// RateLimitRoleConfig is a policy's limit for one role
+type RateLimitRoleConfig struct {
+	Requests int           `mapstructure:"requests"`
+	Period   time.Duration `mapstructure:"period"`
+	Burst    int           `mapstructure:"burst"`
+}
+
+// LoginConfig guards login routes against brute force and credential
+// stuffing. A login whose upstream answers one of FailureStatuses counts as a
+// failure against the account identifier, the first of IdentifierFields found
+// in the JSON body, and against the client IP, each under its own policy.
+type LoginConfig struct {
+	IdentifierFields []string          `mapstructure:"identifier_fields"`
+	FailureStatuses  []int             `mapstructure:"failure_statuses"`
+	Identifier       LoginPolicyConfig `mapstructure:"identifier"`
+	IP               LoginPolicyConfig `mapstructure:"ip"`
+}
+
+// LoginPolicyConfig forgets failures after Window without one. From
+// DelayAfter failures on, each attempt must wait Delay after the last failure,
+// doubling with every further failure up to MaxDelay; LockoutAfter failures
+// lock logins out for Lockout. Zero thresholds disable delays or lockouts.
+type LoginPolicyConfig struct {
+	Window       time.Duration `mapstructure:"window"`
+	DelayAfter   int           `mapstructure:"delay_after"`
+	Delay        time.Duration `mapstructure:"delay"`
+	MaxDelay     time.Duration `mapstructure:"max_delay"`
+	LockoutAfter int           `mapstructure:"lockout_after"`
+	Lockout      time.Duration `mapstructure:"lockout"`
 }
 
 // MetricsConfig holds metrics configuration
@@ -65,91 +317,332 @@ type MetricsConfig struct {
 	Path    string `mapstructure:"path"`
 }
 
-// Load loads configuration from file and environment variables
-func Load() (*Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("./configs")
-	viper.AddConfigPath(".")
+// RouteConfig describes a single public route and the upstream it is proxied to
+type RouteConfig struct {
+	Method   string `mapstructure:"method"`
+	Path     string `mapstructure:"path"`
+	Service  string `mapstructure:"service"`
+	Upstream string `mapstructure:"upstream"`
+	Auth     bool   `mapstructure:"auth"`
+	Role     string `mapstructure:"role"`
 
-	// Set default values
-	setDefaults()
+	// Roles admits callers holding any of the roles; Role is shorthand for one
+	Roles []string `mapstructure:"roles"`
 
-	// Enable reading from environment variables
-	viper.AutomaticEnv()
+	// RevokeToken denylists the caller's token once the upstream answers 2xx,
+	// for logout routes
+	RevokeToken bool `mapstructure:"revoke_token"`
+
+	// LoginProtection counts the route's failed logins under login_protection
+	LoginProtection bool `mapstructure:"login_protection"`
+
+	// Ownership admits only callers who own the resource the route addresses
+	Ownership *OwnershipConfig `mapstructure:"ownership"`
+
+	// RateLimit picks the route's rate limit policy and what a request costs
+	RateLimit *RouteRateLimitConfig `mapstructure:"rate_limit"`
+
+	// Timeout applies to the whole upstream call, retries included. Clients may
+	// ask for a different one with X-Request-Timeout, up to MaxTimeout.
+	Timeout    time.Duration `mapstructure:"timeout"`
+	MaxTimeout time.Duration `mapstructure:"max_timeout"`
+
+	// Headers are applied after the global header policy
+	Headers *HeaderPolicyConfig `mapstructure:"headers"`
+
+	// CircuitBreaker gives the route its own breaker instead of the service's
+	CircuitBreaker *CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+
+	// Priority decides which requests are shed when the service's concurrency
+	// limit is reached: "low" first, then "normal" (the default). "critical"
+	// requests are only held to the service's max_limit.
+	Priority string `mapstructure:"priority"`
+
+	// Transport is "http" (the default) or "grpc"; grpc routes call GRPC.Method
+	Transport string           `mapstructure:"transport"`
+	GRPC      *GRPCRouteConfig `mapstructure:"grpc"`
+}
+
+// PathParams returns the names of the ":name" and "*name" segments of the
+// route's path
+func (r RouteConfig) PathParams() []string {
+	var names []string
+	for _, segment := range strings.Split(r.Path, "/") {
+		if len(segment) >= 2 && (segment[0] == ':' || segment[0] == '*') {
+			names = append(names, segment[1:])
+		}
+	}
+	return names
+}
+
+// GRPCRouteConfig selects the unary method a grpc route calls. The request
+// message is built from the JSON body, then query parameters, then path
+// parameters; Params maps a path parameter to a differently named field.
+type GRPCRouteConfig struct {
+	Method string            `mapstructure:"method"`
+	Params map[string]string `mapstructure:"params"`
+}
+
+// OwnershipConfig restricts a route to callers who own the resource named by
+// the path parameter Param. Each rule applies to callers holding one of its
+// roles, or to every caller when it lists none, and passes when its lookup
+// finds the caller owns the resource. Callers with a BypassRoles role skip
+// the check.
+type OwnershipConfig struct {
+	Param       string                `mapstructure:"param"`
+	BypassRoles []string              `mapstructure:"bypass_roles"`
+	Rules       []OwnershipRuleConfig `mapstructure:"rules"`
+}
+
+// OwnershipRuleConfig checks callers holding any of Roles with a lookup named
+// in authorization.ownership
+type OwnershipRuleConfig struct {
+	Roles  []string `mapstructure:"roles"`
+	Lookup string   `mapstructure:"lookup"`
+}
+
+// RouteRateLimitConfig counts a route's requests against Policy, or the
+// default policy, at Cost requests each (default 1)
+type RouteRateLimitConfig struct {
+	Policy string `mapstructure:"policy"`
+	Cost   int    `mapstructure:"cost"`
+}
+
+// HeaderPolicyConfig holds header rewrite rules for proxied requests and responses
+type HeaderPolicyConfig struct {
+	Request  HeaderRulesConfig `mapstructure:"request"`
+	Response HeaderRulesConfig `mapstructure:"response"`
+}
+
+// HeaderRulesConfig holds header rules, applied in the order remove, rename,
+// set, append. Remove entries ending in "*" match a prefix.
+type HeaderRulesConfig struct {
+	Remove []string          `mapstructure:"remove"`
+	Rename map[string]string `mapstructure:"rename"`
+	Set    map[string]string `mapstructure:"set"`
+	Append map[string]string `mapstructure:"append"`
+}
+
+// configFile is the file Load found, re-read by Reload and watched by Watch
+var configFile string
+
+// Load loads configuration from file and environment variables. Every key can
+// be overridden with a BARIBHARA_ variable (see EnvName), or with a
+// BARIBHARA_..._FILE variable naming a file that holds the value.
+func Load() (*Config, error) {
+	v := viper.New()
+	v.SetConfigName("config")
+	v.SetConfigType("yaml")
+	v.AddConfigPath("./configs")
+	v.AddConfigPath(".")
 
 	// Read config file
-	if err := viper.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return nil, fmt.Errorf("error reading config file: %w", err)
 		}
 	}
+	configFile = v.ConfigFileUsed()
+
+	return load(v)
+}
+
+// Reload re-reads the config file that Load found and returns the new
+// configuration. The running configuration is not touched.
+func Reload() (*Config, error) {
+	v := viper.New()
+	if configFile != "" {
+		v.SetConfigFile(configFile)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("error reading config file: %w", err)
+		}
+	}
+
+	return load(v)
+}
+
+// Watch calls onChange whenever the config file Load found is written
+func Watch(onChange func()) {
+	if configFile == "" {
+		return
+	}
+
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	v.OnConfigChange(func(fsnotify.Event) { onChange() })
+	v.WatchConfig()
+}
+
+// load applies defaults and environment overrides to a freshly read file, so
+// services removed from the file on reload do not linger
+func load(v *viper.Viper) (*Config, error) {
+	// Set default values; services and lookups are only known once the file
+	// is read
+	setDefaults(v)
+	for name := range v.GetStringMap("services") {
+		setServiceDefaults(v, name)
+	}
+	for name := range v.GetStringMap("authorization.ownership") {
+		v.SetDefault("authorization.ownership."+name+".cache_ttl", "1m")
+	}
+	for name := range v.GetStringMap("rate_limit.policies") {
+		v.SetDefault("rate_limit.policies."+name+".key", []string{"ip"})
+		v.SetDefault("rate_limit.policies."+name+".period", "1m")
+	}
+
+	// Enable reading from environment variables
+	if err := bindEnv(v); err != nil {
+		return nil, err
+	}
+	if err := readEnvFiles(v); err != nil {
+		return nil, err
+	}
 
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config, decodeHook); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
 
 // setDefaults sets default configuration values
-func setDefaults() {
+func setDefaults(v *viper.Viper) {
 	// Server defaults
-	viper.SetDefault("server.port", 8080)
-	viper.SetDefault("server.mode", "debug")
-	viper.SetDefault("server.read_timeout", 30)
-	viper.SetDefault("server.write_timeout", 30)
-	viper.SetDefault("server.idle_timeout", 120)
+	v.SetDefault("server.port", 8080)
+	v.SetDefault("server.mode", "debug")
+	v.SetDefault("server.read_timeout", 30)
+	v.SetDefault("server.write_timeout", 30)
+	v.SetDefault("server.idle_timeout", 120)
 
-	// Services defaults
-	viper.SetDefault("services.auth_service.host", "localhost")
-	viper.SetDefault("services.auth_service.port", 3001)
-	viper.SetDefault("services.auth_service.grpc_port", 50051)
+	// Header policy defaults
+	v.SetDefault("server.trusted_proxies", []string{})
+	v.SetDefault("headers.request.remove", []string{"X-Internal-*"})
+	v.SetDefault("headers.response.remove", []string{"X-Powered-By"})
 
-	viper.SetDefault("services.user_service.host", "localhost")
-	viper.SetDefault("services.user_service.port", 3002)
-	viper.SetDefault("services.user_service.grpc_port", 50052)
+	// Redis defaults
+	v.SetDefault("redis.host", "localhost")
+	v.SetDefault("redis.port", 6379)
+	v.SetDefault("redis.password", "")
+	v.SetDefault("redis.db", 0)
 
-	viper.SetDefault("services.property_service.host", "localhost")
-	viper.SetDefault("services.property_service.port", 3003)
-	viper.SetDefault("services.property_service.grpc_port", 50053)
+	// JWT defaults
+	v.SetDefault("jwt.secret", "your-secret-key")
+	v.SetDefault("jwt.expiration", "24h")
+	v.SetDefault("jwt.algorithms", []string{"HS256"})
+	v.SetDefault("jwt.required_claims", []string{"sub", "exp"})
+	v.SetDefault("jwt.leeway", "30s")
+	v.SetDefault("jwt.max_age", "0s")
+	v.SetDefault("jwt.keys.refresh_interval", "5m")
+	v.SetDefault("jwt.keys.grace_period", "24h")
+	v.SetDefault("jwt.validation.mode", "local")
+	v.SetDefault("jwt.validation.service", "auth-service")
+	v.SetDefault("jwt.validation.timeout", "500ms")
+	v.SetDefault("jwt.validation.cache_ttl", "30s")
+	v.SetDefault("jwt.validation.failure_policy", "closed")
+	v.SetDefault("jwt.revocation.failure_policy", "closed")
 
-	viper.SetDefault("services.tenant_service.host", "localhost")
-	viper.SetDefault("services.tenant_service.port", 3004)
-	viper.SetDefault("services.tenant_service.grpc_port", 50054)
+	// Authorization defaults
+	v.SetDefault("authorization.roles_claim", "roles")
+	v.SetDefault("authorization.role_hierarchy", map[string][]string{"super_admin": {"admin"}})
 
-	viper.SetDefault("services.invoice_service.host", "localhost")
-	viper.SetDefault("services.invoice_service.port", 3005)
-	viper.SetDefault("services.invoice_service.grpc_port", 50055)
+	// Identity header defaults
+	v.SetDefault("identity.signing_secret", "")
 
-	viper.SetDefault("services.notification_service.host", "localhost")
-	viper.SetDefault("services.notification_service.port", 3006)
-	viper.SetDefault("services.notification_service.grpc_port", 50056)
+	// Rate limit defaults: 100 requests a minute per client IP
+	v.SetDefault("rate_limit.global_policy", "global")
+	v.SetDefault("rate_limit.default_policy", "default")
+	v.SetDefault("rate_limit.exempt_cidrs", []string{})
+	v.SetDefault("rate_limit.api_key_header", "X-API-Key")
+	v.SetDefault("rate_limit.failure_mode", "local")
+	v.SetDefault("rate_limit.replicas", 1)
+	v.SetDefault("rate_limit.policies.global.key", []string{"ip"})
+	v.SetDefault("rate_limit.policies.global.requests", 600)
+	v.SetDefault("rate_limit.policies.global.period", "1m")
+	v.SetDefault("rate_limit.policies.default.key", []string{"ip"})
+	v.SetDefault("rate_limit.policies.default.requests", 100)
+	v.SetDefault("rate_limit.policies.default.period", "1m")
 
-	viper.SetDefault("services.report_service.host", "localhost")
-	viper.SetDefault("services.report_service.port", 3007)
-	viper.SetDefault("services.report_service.grpc_port", 50057)
+	// Login protection defaults: a few quick retries, then growing delays and
+	// a lockout. IPs get more room, as many users can share one.
+	v.SetDefault("login_protection.identifier_fields", []string{"identifier", "email", "phone"})
+	v.SetDefault("login_protection.failure_statuses", []int{401})
+	v.SetDefault("login_protection.identifier.window", "15m")
+	v.SetDefault("login_protection.identifier.delay_after", 3)
+	v.SetDefault("login_protection.identifier.delay", "1s")
+	v.SetDefault("login_protection.identifier.max_delay", "30s")
+	v.SetDefault("login_protection.identifier.lockout_after", 10)
+	v.SetDefault("login_protection.identifier.lockout", "15m")
+	v.SetDefault("login_protection.ip.window", "15m")
+	v.SetDefault("login_protection.ip.delay_after", 20)
+	v.SetDefault("login_protection.ip.delay", "1s")
+	v.SetDefault("login_protection.ip.max_delay", "10s")
+	v.SetDefault("login_protection.ip.lockout_after", 100)
+	v.SetDefault("login_protection.ip.lockout", "15m")
 
-	viper.SetDefault("services.admin_service.host", "localhost")
-	viper.SetDefault("services.admin_service.port", 3008)
-	viper.SetDefault("services.admin_service.grpc_port", 50058)
+	// Metrics defaults
+	v.SetDefault("metrics.enabled", true)
+	v.SetDefault("metrics.path", "/metrics")
+}
 
-	viper.SetDefault("services.caretaker_service.host", "localhost")
-	viper.SetDefault("services.caretaker_service.port", 3009)
-	viper.SetDefault("services.caretaker_service.grpc_port", 50059)
+// setServiceDefaults sets the timeout, balancing, health check, circuit
+// breaker, concurrency, retry and transport defaults of a service
+func setServiceDefaults(v *viper.Viper, name string) {
+	service := "services." + name + "."
+	v.SetDefault(service+"timeout", "30s")
+	v.SetDefault(service+"balancer", "round_robin")
+	v.SetDefault(service+"grpc_pool_size", 2)
+	v.SetDefault(service+"grpc_max_body_bytes", 4<<20)
 
-	// Redis defaults
-	viper.SetDefault("redis.host", "localhost")
-	viper.SetDefault("redis.port", 6379)
-	viper.SetDefault("redis.password", "")
-	viper.SetDefault("redis.db", 0)
+	health := service + "health_check."
+	v.SetDefault(health+"enabled", true)
+	v.SetDefault(health+"path", "/health")
+	v.SetDefault(health+"interval", "10s")
+	v.SetDefault(health+"timeout", "2s")
+	v.SetDefault(health+"unhealthy_threshold", 3)
+	v.SetDefault(health+"healthy_threshold", 2)
 
-	// JWT defaults
-	viper.SetDefault("jwt.secret", "your-secret-key")
-	viper.SetDefault("jwt.expiration", "24h")
+	breaker := service + "circuit_breaker."
+	v.SetDefault(breaker+"enabled", true)
+	v.SetDefault(breaker+"consecutive_failures", 5)
+	v.SetDefault(breaker+"error_rate_threshold", 0.5)
+	v.SetDefault(breaker+"min_requests", 20)
+	v.SetDefault(breaker+"window", "30s")
+	v.SetDefault(breaker+"cool_down", "30s")
+	v.SetDefault(breaker+"half_open_requests", 1)
 
-	// Metrics defaults
-	viper.SetDefault("metrics.enabled", true)
-	viper.SetDefault("metrics.path", "/metrics")
+	concurrency := service + "concurrency."
+	v.SetDefault(concurrency+"enabled", true)
+	v.SetDefault(concurrency+"initial_limit", 100)
+	v.SetDefault(concurrency+"min_limit", 10)
+	v.SetDefault(concurrency+"max_limit", 1000)
+	v.SetDefault(concurrency+"tolerance", 2.0)
+	v.SetDefault(concurrency+"window", "1s")
+	v.SetDefault(concurrency+"low_priority_share", 0.5)
+
+	retry := service + "retry."
+	v.SetDefault(retry+"enabled", true)
+	v.SetDefault(retry+"max_attempts", 3)
+	v.SetDefault(retry+"initial_backoff", "50ms")
+	v.SetDefault(retry+"max_backoff", "1s")
+	v.SetDefault(retry+"retry_on", []int{502, 503, 504})
+	v.SetDefault(retry+"methods", []string{"GET", "HEAD", "PUT", "DELETE"})
+	v.SetDefault(retry+"max_body_bytes", 1<<20)
+	v.SetDefault(retry+"budget_ratio", 0.2)
+	v.SetDefault(retry+"budget_min_retries", 10)
+	v.SetDefault(retry+"budget_window", "10s")
+
+	transport := service + "transport."
+	v.SetDefault(transport+"max_idle_conns", 100)
+	v.SetDefault(transport+"max_idle_conns_per_host", 32)
+	v.SetDefault(transport+"max_conns_per_host", 0)
+	v.SetDefault(transport+"idle_conn_timeout", "90s")
+	v.SetDefault(transport+"keep_alive", "30s")
+	v.SetDefault(transport+"dial_timeout", "5s")
+	v.SetDefault(transport+"tls_handshake_timeout", "5s")
+	v.SetDefault(transport+"response_header_timeout", "30s")
 }
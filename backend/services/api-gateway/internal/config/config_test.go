@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadServiceRegistry(t *testing.T) {
+	path := useConfigFile(t, `
+services:
+  tenant-service:
+    host: "localhost"
+    port: 3004
+  dashboard-service:
+    host: "localhost"
+    port: 3010
+    timeout: "5s"
+    health_check:
+      enabled: false
+jwt:
+  secret: "0123456789abcdef0123456789abcdef"
+`)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Services, 2)
+	dashboard := cfg.Services["dashboard-service"]
+	assert.Equal(t, 3010, dashboard.Port)
+	assert.Equal(t, 5*time.Second, dashboard.Timeout)
+	assert.False(t, dashboard.HealthCheck.Enabled)
+
+	// Defaults apply to every declared service
+	assert.Equal(t, "round_robin", dashboard.Balancer)
+	assert.True(t, dashboard.CircuitBreaker.Enabled)
+	assert.Equal(t, 30*time.Second, cfg.Services["tenant-service"].Timeout)
+
+	// A service removed from the file is gone after a reload
+	require.NoError(t, os.WriteFile(path, []byte(`
+services:
+  tenant-service:
+    host: "localhost"
+    port: 3004
+jwt:
+  secret: "0123456789abcdef0123456789abcdef"
+`), 0o600))
+
+	cfg, err = Reload()
+	require.NoError(t, err)
+	assert.Len(t, cfg.Services, 1)
+	assert.Contains(t, cfg.Services, "tenant-service")
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Diff lists the settings that differ between two configurations as
+// "key: old -> new", using the config file's key names. Secrets are redacted.
+func Diff(old, updated *Config) []string {
+	before := make(map[string]string)
+	after := make(map[string]string)
+	flatten(reflect.ValueOf(*old), "", before)
+	flatten(reflect.ValueOf(*updated), "", after)
+
+	keys := make(map[string]bool)
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+
+	var changes []string
+	for key := range keys {
+		oldValue, hadOld := before[key]
+		newValue, hasNew := after[key]
+		if hadOld && hasNew && oldValue == newValue {
+			continue
+		}
+		if !hadOld {
+			oldValue = "<unset>"
+		}
+		if !hasNew {
+			newValue = "<unset>"
+		}
+		if isSecret(key) {
+			oldValue, newValue = "<redacted>", "<redacted>"
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s -> %s", key, oldValue, newValue))
+	}
+	sort.Strings(changes)
+
+	return changes
+}
+
+// flatten walks a config value and records every leaf under its dotted key
+func flatten(value reflect.Value, prefix string, out map[string]string) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !value.IsNil() {
+			flatten(value.Elem(), prefix, out)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := field.Tag.Get("mapstructure")
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			flatten(value.Field(i), join(prefix, name), out)
+		}
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() != reflect.Struct {
+			out[prefix] = fmt.Sprint(value.Interface())
+			return
+		}
+		for i := 0; i < value.Len(); i++ {
+			flatten(value.Index(i), fmt.Sprintf("%s[%d]", prefix, i), out)
+		}
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			flatten(value.MapIndex(key), join(prefix, fmt.Sprint(key.Interface())), out)
+		}
+	default:
+		out[prefix] = fmt.Sprint(value.Interface())
+	}
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func isSecret(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "secret") || strings.Contains(key, "password")
+}
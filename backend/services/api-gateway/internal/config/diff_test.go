@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	old := &Config{}
+	old.Server.Port = 8080
+	old.JWT.Secret = "old-secret"
+	old.Services = ServicesConfig{"tenant-service": {Timeout: 30 * time.Second}}
+	old.Routes = []RouteConfig{{Method: "GET", Path: "/api/v1/tenants"}}
+
+	updated := &Config{}
+	updated.Server.Port = 8080
+	updated.JWT.Secret = "new-secret"
+	updated.Services = ServicesConfig{"tenant-service": {Timeout: 10 * time.Second}}
+	updated.Routes = []RouteConfig{
+		{Method: "GET", Path: "/api/v1/tenants"},
+		{Method: "POST", Path: "/api/v1/tenants"},
+	}
+
+	changes := Diff(old, updated)
+
+	assert.Contains(t, changes, "jwt.secret: <redacted> -> <redacted>")
+	assert.Contains(t, changes, "services.tenant-service.timeout: 30s -> 10s")
+	assert.Contains(t, changes, "routes[1].method: <unset> -> POST")
+	assert.NotContains(t, changes, "routes[0].method: GET -> GET")
+	assert.Empty(t, Diff(old, old))
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix is prepended to every environment variable the gateway reads
+const EnvPrefix = "BARIBHARA"
+
+// fileSuffix marks an environment variable holding the path of a file whose
+// contents are the value, for secrets mounted into the container
+const fileSuffix = "_FILE"
+
+// envReplacer turns a config key into an environment variable name
+var envReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// EnvName returns the environment variable that overrides a config key, e.g.
+// services.invoice-service.host -> BARIBHARA_SERVICES_INVOICE_SERVICE_HOST
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(envReplacer.Replace(key))
+}
+
+// bindEnv makes every key in Config overridable from the environment. Lists of
+// objects and maps (routes, endpoints, header rules) take a JSON value; other
+// lists take comma-separated values. Services must be declared in the config
+// file; the environment can then override any of their settings.
+func bindEnv(v *viper.Viper) error {
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(envReplacer)
+	v.AutomaticEnv()
+
+	for _, key := range envKeys(v, reflect.TypeOf(Config{}), "") {
+		if err := v.BindEnv(key); err != nil {
+			return fmt.Errorf("error binding %s: %w", EnvName(key), err)
+		}
+	}
+
+	return nil
+}
+
+// readEnvFiles sets every key whose *_FILE variable is set to the contents of
+// that file. It runs on each load so rotated secrets are picked up on reload.
+func readEnvFiles(v *viper.Viper) error {
+	for _, key := range envKeys(v, reflect.TypeOf(Config{}), "") {
+		name := EnvName(key)
+		path := os.Getenv(name + fileSuffix)
+		if path == "" {
+			continue
+		}
+		if os.Getenv(name) != "" {
+			return fmt.Errorf("both %s and %s%s are set", name, name, fileSuffix)
+		}
+
+		value, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading %s%s: %w", name, fileSuffix, err)
+		}
+		v.Set(key, strings.TrimRight(string(value), "\r\n"))
+	}
+
+	return nil
+}
+
+// envKeys lists the config keys of a struct type, stopping at values that are
+// set as a whole. Maps of structs (services) expand to the entries v knows of.
+func envKeys(v *viper.Viper, t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := join(prefix, field.Tag.Get("mapstructure"))
+		switch {
+		case field.Type.Kind() == reflect.Struct:
+			keys = append(keys, envKeys(v, field.Type, key)...)
+		case field.Type.Kind() == reflect.Map && field.Type.Elem().Kind() == reflect.Struct:
+			for name := range v.GetStringMap(key) {
+				keys = append(keys, envKeys(v, field.Type.Elem(), join(key, name))...)
+			}
+		default:
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// jsonHook decodes JSON strings from the environment into lists and maps
+func jsonHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String || (to.Kind() != reflect.Slice && to.Kind() != reflect.Map) {
+		return data, nil
+	}
+
+	value := strings.TrimSpace(data.(string))
+	if !strings.HasPrefix(value, "[") && !strings.HasPrefix(value, "{") {
+		return data, nil
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return decoded, nil
+}
+
+// decodeHook is viper's default hook with JSON support for lists and maps
+var decodeHook = viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+	jsonHook,
+	mapstructure.StringToTimeDurationHookFunc(),
+	mapstructure.StringToSliceHookFunc(","),
+))
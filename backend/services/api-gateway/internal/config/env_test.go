@@ -0,0 +1,76 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "BARIBHARA_SERVICES_INVOICE_SERVICE_HOST", EnvName("services.invoice-service.host"))
+	assert.Equal(t, "BARIBHARA_JWT_SECRET", EnvName("jwt.secret"))
+}
+
+// useConfigFile runs the test from a directory holding configs/config.yaml
+func useConfigFile(t *testing.T, content string) string {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "configs", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	return path
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	useConfigFile(t, `
+services:
+  invoice-service:
+    host: "localhost"
+    port: 3005
+  tenant-service:
+    host: "localhost"
+    port: 3004
+`)
+
+	secretFile := filepath.Join(t.TempDir(), "jwt-secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("0123456789abcdef0123456789abcdef\n"), 0o600))
+
+	t.Setenv("BARIBHARA_JWT_SECRET_FILE", secretFile)
+	t.Setenv("BARIBHARA_SERVER_PORT", "3000")
+	t.Setenv("BARIBHARA_SERVER_TRUSTED_PROXIES", "10.0.0.0/8,192.168.1.1")
+	t.Setenv("BARIBHARA_SERVICES_INVOICE_SERVICE_HOST", "invoice-service")
+	t.Setenv("BARIBHARA_SERVICES_INVOICE_SERVICE_RETRY_MAX_BACKOFF", "2s")
+	t.Setenv("BARIBHARA_SERVICES_TENANT_SERVICE_ENDPOINTS",
+		`[{"host": "tenant-0", "port": 3004, "weight": 2}, {"host": "tenant-1", "port": 3004}]`)
+	t.Setenv("BARIBHARA_ROUTES",
+		`[{"method": "GET", "path": "/api/v1/tenants", "service": "tenant-service", "upstream": "/api/v1/tenants", "timeout": "3s"}]`)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", cfg.JWT.Secret)
+	assert.Equal(t, 3000, cfg.Server.Port)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, cfg.Server.TrustedProxies)
+	assert.Equal(t, "invoice-service", cfg.Services["invoice-service"].Host)
+	assert.Equal(t, 2*time.Second, cfg.Services["invoice-service"].Retry.MaxBackoff)
+	assert.Equal(t, []EndpointConfig{
+		{Host: "tenant-0", Port: 3004, Weight: 2},
+		{Host: "tenant-1", Port: 3004},
+	}, cfg.Services["tenant-service"].Endpoints)
+	require.Len(t, cfg.Routes, 1)
+	assert.Equal(t, 3*time.Second, cfg.Routes[0].Timeout)
+
+	// A value and a file for the same key is ambiguous
+	t.Setenv("BARIBHARA_JWT_SECRET", "fedcba9876543210fedcba9876543210")
+	_, err = Reload()
+	assert.ErrorContains(t, err, "both BARIBHARA_JWT_SECRET and BARIBHARA_JWT_SECRET_FILE are set")
+}
@@ -0,0 +1,597 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// minSecretLength is the shortest JWT secret accepted; HS256 keys should carry
+// at least 256 bits
+const minSecretLength = 32
+
+// placeholderSecrets are example secrets from the repo's configs and docs,
+// refused in production
+var placeholderSecrets = []string{
+	"your-secret-key",
+	"your-secret-key-change-in-production",
+	"your-super-secret-jwt-key-change-in-production",
+	"secret",
+	"changeme",
+}
+
+var serverModes = []string{"debug", "release", "test", "production"}
+
+// serviceName keeps service names unambiguous as environment variable names
+var serviceName = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// hmacAlgorithms are verified with the shared secret, the other jwtAlgorithms
+// with public keys
+var hmacAlgorithms = []string{"HS256", "HS384", "HS512"}
+
+var jwtAlgorithms = []string{
+	"HS256", "HS384", "HS512",
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+}
+
+var tokenValidationModes = []string{"local", "remote"}
+
+var failurePolicies = []string{"open", "closed"}
+
+var balancers = []string{"round_robin", "weighted_round_robin", "least_outstanding", "consistent_hash"}
+
+// rateLimitKeys are the parts a rate limit policy can key callers by
+var rateLimitKeys = []string{"ip", "user", "api_key"}
+
+var rateLimitFailureModes = []string{"open", "local", "closed"}
+
+var routeMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+var routeTransports = []string{"http", "grpc"}
+
+var routePriorities = []string{"critical", "normal", "low"}
+
+// ValidationError lists every problem found in a configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// validator collects problems keyed by their config file path
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(field, format string, args ...interface{}) {
+	v.problems = append(v.problems, field+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) port(field string, port int) {
+	if port < 1 || port > 65535 {
+		v.addf(field, "must be between 1 and 65535, got %d", port)
+	}
+}
+
+func (v *validator) required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.addf(field, "is required")
+	}
+}
+
+func (v *validator) positive(field string, d time.Duration) {
+	if d <= 0 {
+		v.addf(field, "must be a positive duration, got %s", d)
+	}
+}
+
+func (v *validator) nonNegative(field string, n int64) {
+	if n < 0 {
+		v.addf(field, "must not be negative, got %d", n)
+	}
+}
+
+func (v *validator) oneOf(field, value string, allowed []string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.addf(field, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+func (v *validator) cidrs(field string, cidrs []string) {
+	for i, cidr := range cidrs {
+		if _, err := ParseCIDR(cidr); err != nil {
+			v.addf(fmt.Sprintf("%s[%d]", field, i), "%s", err)
+		}
+	}
+}
+
+// Validate checks the configuration and reports every problem at once, by
+// config file path. Durations that do not parse are already rejected when the
+// file is unmarshaled.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	c.validateServer(v)
+	names := make([]string, 0, len(c.Services))
+	for name := range c.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !serviceName.MatchString(name) {
+			v.addf("services."+name, "name must be lowercase letters, digits and hyphens")
+		}
+		validateService(v, "services."+name, c.Services[name])
+	}
+	c.validateRedis(v)
+	c.validateJWT(v)
+	v.required("authorization.roles_claim", c.Authorization.RolesClaim)
+	roles := make([]string, 0, len(c.Authorization.RoleHierarchy))
+	for role := range c.Authorization.RoleHierarchy {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		for i, included := range c.Authorization.RoleHierarchy[role] {
+			v.required(fmt.Sprintf("authorization.role_hierarchy.%s[%d]", role, i), included)
+		}
+	}
+	c.validateOwnership(v)
+	c.validateRateLimit(v)
+	c.validateLogin(v)
+	if secret := c.Identity.SigningSecret; secret != "" {
+		if len(secret) < minSecretLength {
+			v.addf("identity.signing_secret", "must be at least %d characters, got %d", minSecretLength, len(secret))
+		}
+		if c.Server.Mode == "production" && isPlaceholderSecret(secret) {
+			v.addf("identity.signing_secret", "is a placeholder value and must be replaced in production")
+		}
+	}
+
+	c.validateRoutes(v)
+
+	if c.Metrics.Enabled && !strings.HasPrefix(c.Metrics.Path, "/") {
+		v.addf("metrics.path", "must start with /, got %q", c.Metrics.Path)
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+func (c *Config) validateServer(v *validator) {
+	v.port("server.port", c.Server.Port)
+	v.oneOf("server.mode", c.Server.Mode, serverModes)
+	v.nonNegative("server.read_timeout", int64(c.Server.ReadTimeout))
+	v.nonNegative("server.write_timeout", int64(c.Server.WriteTimeout))
+	v.nonNegative("server.idle_timeout", int64(c.Server.IdleTimeout))
+
+	v.cidrs("server.trusted_proxies", c.Server.TrustedProxies)
+}
+
+func validateService(v *validator, field string, s ServiceConfig) {
+	if len(s.Endpoints) == 0 {
+		v.required(field+".host", s.Host)
+		v.port(field+".port", s.Port)
+	}
+	for i, endpoint := range s.Endpoints {
+		prefix := fmt.Sprintf("%s.endpoints[%d]", field, i)
+		v.required(prefix+".host", endpoint.Host)
+		v.port(prefix+".port", endpoint.Port)
+		v.nonNegative(prefix+".weight", int64(endpoint.Weight))
+	}
+	if s.GRPCPort != 0 {
+		v.port(field+".grpc_port", s.GRPCPort)
+		if s.GRPCMaxBody < 1 {
+			v.addf(field+".grpc_max_body_bytes", "must be positive, got %d", s.GRPCMaxBody)
+		}
+	}
+	v.nonNegative(field+".grpc_pool_size", int64(s.GRPCPoolSize))
+	v.positive(field+".timeout", s.Timeout)
+	v.oneOf(field+".balancer", s.Balancer, balancers)
+
+	t := s.Transport
+	v.nonNegative(field+".transport.max_idle_conns", int64(t.MaxIdleConns))
+	v.nonNegative(field+".transport.max_idle_conns_per_host", int64(t.MaxIdleConnsPerHost))
+	v.nonNegative(field+".transport.max_conns_per_host", int64(t.MaxConnsPerHost))
+	v.nonNegative(field+".transport.idle_conn_timeout", int64(t.IdleConnTimeout))
+	v.nonNegative(field+".transport.keep_alive", int64(t.KeepAlive))
+	v.nonNegative(field+".transport.dial_timeout", int64(t.DialTimeout))
+	v.nonNegative(field+".transport.tls_handshake_timeout", int64(t.TLSHandshakeTimeout))
+	v.nonNegative(field+".transport.response_header_timeout", int64(t.ResponseHeaderTimeout))
+
+	if h := s.HealthCheck; h.Enabled {
+		if !strings.HasPrefix(h.Path, "/") {
+			v.addf(field+".health_check.path", "must start with /, got %q", h.Path)
+		}
+		v.positive(field+".health_check.interval", h.Interval)
+		v.positive(field+".health_check.timeout", h.Timeout)
+		if h.UnhealthyThreshold < 1 {
+			v.addf(field+".health_check.unhealthy_threshold", "must be at least 1, got %d", h.UnhealthyThreshold)
+		}
+		if h.HealthyThreshold < 1 {
+			v.addf(field+".health_check.healthy_threshold", "must be at least 1, got %d", h.HealthyThreshold)
+		}
+	}
+
+	validateCircuitBreaker(v, field+".circuit_breaker", s.CircuitBreaker)
+
+	if cc := s.Concurrency; cc.Enabled {
+		if cc.MinLimit < 1 {
+			v.addf(field+".concurrency.min_limit", "must be at least 1, got %d", cc.MinLimit)
+		}
+		if cc.MaxLimit < cc.MinLimit {
+			v.addf(field+".concurrency.max_limit", "must not be less than min_limit (%d), got %d", cc.MinLimit, cc.MaxLimit)
+		}
+		if cc.InitialLimit < cc.MinLimit || cc.InitialLimit > cc.MaxLimit {
+			v.addf(field+".concurrency.initial_limit", "must be between min_limit and max_limit, got %d", cc.InitialLimit)
+		}
+		if cc.Tolerance < 1 {
+			v.addf(field+".concurrency.tolerance", "must be at least 1, got %g", cc.Tolerance)
+		}
+		v.positive(field+".concurrency.window", cc.Window)
+		if cc.LowPriorityShare <= 0 || cc.LowPriorityShare > 1 {
+			v.addf(field+".concurrency.low_priority_share", "must be above 0 and at most 1, got %g", cc.LowPriorityShare)
+		}
+	}
+
+	if tls := s.TLS; tls.Enabled && (tls.CertFile == "") != (tls.KeyFile == "") {
+		v.addf(field+".tls", "cert_file and key_file must be set together")
+	}
+
+	if r := s.Retry; r.Enabled {
+		if r.MaxAttempts < 1 {
+			v.addf(field+".retry.max_attempts", "must be at least 1, got %d", r.MaxAttempts)
+		}
+		v.nonNegative(field+".retry.initial_backoff", int64(r.InitialBackoff))
+		if r.MaxBackoff < r.InitialBackoff {
+			v.addf(field+".retry.max_backoff", "must not be less than initial_backoff (%s), got %s", r.InitialBackoff, r.MaxBackoff)
+		}
+		for i, status := range r.RetryOn {
+			if status < 100 || status > 599 {
+				v.addf(fmt.Sprintf("%s.retry.retry_on[%d]", field, i), "must be an HTTP status code, got %d", status)
+			}
+		}
+		v.nonNegative(field+".retry.max_body_bytes", r.MaxBodyBytes)
+		if r.BudgetRatio < 0 {
+			v.addf(field+".retry.budget_ratio", "must not be negative, got %g", r.BudgetRatio)
+		}
+		v.nonNegative(field+".retry.budget_min_retries", int64(r.BudgetMinRetries))
+	}
+}
+
+func validateCircuitBreaker(v *validator, field string, b CircuitBreakerConfig) {
+	if !b.Enabled {
+		return
+	}
+	v.nonNegative(field+".consecutive_failures", int64(b.ConsecutiveFailures))
+	if b.ErrorRateThreshold < 0 || b.ErrorRateThreshold > 1 {
+		v.addf(field+".error_rate_threshold", "must be between 0 and 1, got %g", b.ErrorRateThreshold)
+	}
+	v.nonNegative(field+".min_requests", int64(b.MinRequests))
+	v.nonNegative(field+".window", int64(b.Window))
+	v.positive(field+".cool_down", b.CoolDown)
+	if b.HalfOpenRequests < 1 {
+		v.addf(field+".half_open_requests", "must be at least 1, got %d", b.HalfOpenRequests)
+	}
+}
+
+func (c *Config) validateRedis(v *validator) {
+	v.required("redis.host", c.Redis.Host)
+	v.port("redis.port", c.Redis.Port)
+	v.nonNegative("redis.db", int64(c.Redis.DB))
+}
+
+func (c *Config) validateJWT(v *validator) {
+	usesSecret, usesKeys := false, false
+	if len(c.JWT.Algorithms) == 0 {
+		v.addf("jwt.algorithms", "must list at least one algorithm")
+	}
+	for i, alg := range c.JWT.Algorithms {
+		v.oneOf(fmt.Sprintf("jwt.algorithms[%d]", i), alg, jwtAlgorithms)
+		if contains(hmacAlgorithms, alg) {
+			usesSecret = true
+		} else if contains(jwtAlgorithms, alg) {
+			usesKeys = true
+		}
+	}
+
+	// The secret only matters while HMAC tokens are accepted
+	secret := c.JWT.Secret
+	if usesSecret && len(secret) < minSecretLength {
+		v.addf("jwt.secret", "must be at least %d characters, got %d", minSecretLength, len(secret))
+	}
+	if usesSecret && c.Server.Mode == "production" && isPlaceholderSecret(secret) {
+		v.addf("jwt.secret", "is a placeholder value and must be replaced in production")
+	}
+	v.positive("jwt.expiration", c.JWT.Expiration)
+
+	keys := c.JWT.Keys
+	if usesKeys && !keys.Configured() {
+		v.addf("jwt.keys", "pem, jwks_file or jwks_url is required for %s", strings.Join(c.JWT.Algorithms, ", "))
+	}
+	for i, key := range keys.PEM {
+		v.required(fmt.Sprintf("jwt.keys.pem[%d].kid", i), key.KID)
+		v.required(fmt.Sprintf("jwt.keys.pem[%d].file", i), key.File)
+	}
+	if keys.JWKSURL != "" {
+		if u, err := url.Parse(keys.JWKSURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.addf("jwt.keys.jwks_url", "must be an http or https URL, got %q", keys.JWKSURL)
+		}
+	}
+	if keys.Configured() {
+		v.positive("jwt.keys.refresh_interval", keys.RefreshInterval)
+		v.nonNegative("jwt.keys.grace_period", int64(keys.GracePeriod))
+	}
+	for i, claim := range c.JWT.RequiredClaims {
+		v.required(fmt.Sprintf("jwt.required_claims[%d]", i), claim)
+	}
+	v.nonNegative("jwt.leeway", int64(c.JWT.Leeway))
+	v.nonNegative("jwt.max_age", int64(c.JWT.MaxAge))
+
+	v.oneOf("jwt.revocation.failure_policy", c.JWT.Revocation.FailurePolicy, failurePolicies)
+
+	tv := c.JWT.Validation
+	v.oneOf("jwt.validation.mode", tv.Mode, tokenValidationModes)
+	if tv.Mode == "remote" {
+		if _, ok := c.Services[tv.Service]; !ok {
+			v.addf("jwt.validation.service", "must name a configured service, got %q", tv.Service)
+		} else if c.Services[tv.Service].GRPCPort == 0 {
+			v.addf("jwt.validation.service", "%s has no grpc_port", tv.Service)
+		}
+		v.positive("jwt.validation.timeout", tv.Timeout)
+		v.nonNegative("jwt.validation.cache_ttl", int64(tv.CacheTTL))
+		v.oneOf("jwt.validation.failure_policy", tv.FailurePolicy, failurePolicies)
+	}
+}
+
+// validateRoutes checks the route table, including that no two routes share
+// a method and path
+func (c *Config) validateRoutes(v *validator) {
+	seen := make(map[string]bool)
+	for i, route := range c.Routes {
+		field := fmt.Sprintf("routes[%d]", i)
+		method := strings.ToUpper(route.Method)
+		v.oneOf(field+".method", method, routeMethods)
+		if !strings.HasPrefix(route.Path, "/") {
+			v.addf(field+".path", "must start with /, got %q", route.Path)
+		}
+		if route.Upstream != "" && !strings.HasPrefix(route.Upstream, "/") {
+			v.addf(field+".upstream", "must start with /, got %q", route.Upstream)
+		}
+		if _, ok := c.Services[route.Service]; !ok {
+			v.addf(field+".service", "unknown service %q", route.Service)
+		}
+		v.nonNegative(field+".timeout", int64(route.Timeout))
+		v.nonNegative(field+".max_timeout", int64(route.MaxTimeout))
+		if route.MaxTimeout > 0 && route.MaxTimeout < route.Timeout {
+			v.addf(field+".max_timeout", "must not be shorter than timeout %s, got %s", route.Timeout, route.MaxTimeout)
+		}
+		if !route.Auth {
+			if route.Role != "" || len(route.Roles) > 0 {
+				v.addf(field+".roles", "require auth")
+			}
+			if route.RevokeToken {
+				v.addf(field+".revoke_token", "requires auth")
+			}
+			if route.Ownership != nil {
+				v.addf(field+".ownership", "requires auth")
+			}
+		}
+		if route.Transport != "" {
+			v.oneOf(field+".transport", route.Transport, routeTransports)
+		}
+		if route.Transport == "grpc" && (route.GRPC == nil || route.GRPC.Method == "") {
+			v.addf(field+".grpc.method", "is required for grpc transport")
+		}
+		if route.CircuitBreaker != nil {
+			validateCircuitBreaker(v, field+".circuit_breaker", *route.CircuitBreaker)
+		}
+		if route.Priority != "" {
+			v.oneOf(field+".priority", route.Priority, routePriorities)
+		}
+		if r := route.RateLimit; r != nil {
+			name := r.Policy
+			if name == "" {
+				name = c.RateLimit.DefaultPolicy
+			}
+			policy, ok := c.RateLimit.Policies[name]
+			if !ok && r.Policy != "" {
+				v.addf(field+".rate_limit.policy", "unknown policy %q", r.Policy)
+			}
+			v.nonNegative(field+".rate_limit.cost", int64(r.Cost))
+			// A request costing more than the burst could never be allowed
+			burst := policy.Burst
+			if burst == 0 {
+				burst = policy.Requests
+			}
+			if ok && r.Cost > burst {
+				v.addf(field+".rate_limit.cost", "%d exceeds the burst of policy %q, %d", r.Cost, name, burst)
+			}
+		}
+		if o := route.Ownership; o != nil {
+			v.required(field+".ownership.param", o.Param)
+			if o.Param != "" && !contains(route.PathParams(), o.Param) {
+				v.addf(field+".ownership.param", "%q is not a parameter of %s", o.Param, route.Path)
+			}
+			if len(o.Rules) == 0 {
+				v.addf(field+".ownership.rules", "at least one rule is required")
+			}
+			for j, rule := range o.Rules {
+				if _, ok := c.Authorization.Ownership[rule.Lookup]; !ok {
+					v.addf(fmt.Sprintf("%s.ownership.rules[%d].lookup", field, j), "unknown lookup %q", rule.Lookup)
+				}
+			}
+		}
+
+		key := method + " " + route.Path
+		if seen[key] {
+			v.addf(field, "duplicate route %s", key)
+		}
+		seen[key] = true
+	}
+}
+
+// validateOwnership checks the ownership lookups in name order
+func (c *Config) validateOwnership(v *validator) {
+	names := make([]string, 0, len(c.Authorization.Ownership))
+	for name := range c.Authorization.Ownership {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		lookup := c.Authorization.Ownership[name]
+		field := "authorization.ownership." + name
+
+		if _, ok := c.Services[lookup.Service]; !ok {
+			v.addf(field+".service", "unknown service %q", lookup.Service)
+		}
+
+		hasGRPC := lookup.GRPC != nil && lookup.GRPC.Method != ""
+		switch {
+		case lookup.Path == "" && !hasGRPC:
+			v.addf(field, "path or grpc.method is required")
+		case lookup.Path != "" && hasGRPC:
+			v.addf(field, "path and grpc.method are mutually exclusive")
+		case lookup.Path != "" && !strings.HasPrefix(lookup.Path, "/"):
+			v.addf(field+".path", "must start with /, got %q", lookup.Path)
+		}
+
+		switch {
+		case (lookup.IDs == "") == (lookup.Owner == ""):
+			v.addf(field, "exactly one of ids and owner is required")
+		case lookup.IDs != "" && !lookupUses(lookup, "user_id"):
+			v.addf(field, "ids lookups must pass user_id")
+		case lookup.Owner != "" && !lookupUses(lookup, "resource_id"):
+			v.addf(field, "owner lookups must pass resource_id")
+		}
+
+		v.nonNegative(field+".cache_ttl", int64(lookup.CacheTTL))
+	}
+}
+
+// validateRateLimit checks the exempt CIDRs and the policies in name order
+func (c *Config) validateRateLimit(v *validator) {
+	r := c.RateLimit
+	if r.GlobalPolicy != "" {
+		if policy, ok := r.Policies[r.GlobalPolicy]; !ok {
+			v.addf("rate_limit.global_policy", "unknown policy %q", r.GlobalPolicy)
+		} else if contains(policy.Key, "user") {
+			// It runs before auth, when there is no user yet
+			v.addf("rate_limit.global_policy", "policy %q must not be keyed by user", r.GlobalPolicy)
+		}
+	}
+	if _, ok := r.Policies[r.DefaultPolicy]; !ok {
+		v.addf("rate_limit.default_policy", "unknown policy %q", r.DefaultPolicy)
+	}
+	v.oneOf("rate_limit.failure_mode", r.FailureMode, rateLimitFailureModes)
+	if r.Replicas < 1 {
+		v.addf("rate_limit.replicas", "must be positive, got %d", r.Replicas)
+	}
+	v.cidrs("rate_limit.exempt_cidrs", r.ExemptCIDRs)
+
+	names := make([]string, 0, len(r.Policies))
+	for name := range r.Policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		policy := r.Policies[name]
+		field := "rate_limit.policies." + name
+		if len(policy.Key) == 0 {
+			v.addf(field+".key", "at least one of %s is required", strings.Join(rateLimitKeys, ", "))
+		}
+		for i, key := range policy.Key {
+			v.oneOf(fmt.Sprintf("%s.key[%d]", field, i), key, rateLimitKeys)
+		}
+		validateRateLimit(v, field, policy.Requests, policy.Period, policy.Burst)
+
+		roles := make([]string, 0, len(policy.Roles))
+		for role := range policy.Roles {
+			roles = append(roles, role)
+		}
+		sort.Strings(roles)
+		for _, role := range roles {
+			limit := policy.Roles[role]
+			validateRateLimit(v, field+".roles."+role, limit.Requests, limit.Period, limit.Burst)
+		}
+	}
+}
+
+func validateRateLimit(v *validator, field string, requests int, period time.Duration, burst int) {
+	if requests <= 0 {
+		v.addf(field+".requests", "must be positive, got %d", requests)
+	}
+	v.positive(field+".period", period)
+	v.nonNegative(field+".burst", int64(burst))
+}
+
+// validateLogin checks the login failure statuses and policies
+func (c *Config) validateLogin(v *validator) {
+	for i, field := range c.Login.IdentifierFields {
+		v.required(fmt.Sprintf("login_protection.identifier_fields[%d]", i), field)
+	}
+	for i, status := range c.Login.FailureStatuses {
+		if status < 400 || status > 599 {
+			v.addf(fmt.Sprintf("login_protection.failure_statuses[%d]", i), "must be between 400 and 599, got %d", status)
+		}
+	}
+	validateLoginPolicy(v, "login_protection.identifier", c.Login.Identifier)
+	validateLoginPolicy(v, "login_protection.ip", c.Login.IP)
+}
+
+func validateLoginPolicy(v *validator, field string, p LoginPolicyConfig) {
+	v.positive(field+".window", p.Window)
+	v.nonNegative(field+".delay_after", int64(p.DelayAfter))
+	v.nonNegative(field+".lockout_after", int64(p.LockoutAfter))
+	if p.DelayAfter > 0 {
+		v.positive(field+".delay", p.Delay)
+		if p.MaxDelay < p.Delay {
+			v.addf(field+".max_delay", "must not be shorter than delay %s, got %s", p.Delay, p.MaxDelay)
+		}
+	}
+	if p.LockoutAfter > 0 {
+		v.positive(field+".lockout", p.Lockout)
+	}
+}
+
+// lookupUses reports whether a lookup passes the named parameter upstream
+func lookupUses(lookup OwnershipLookupConfig, param string) bool {
+	if lookup.GRPC != nil {
+		_, ok := lookup.GRPC.Params[param]
+		return ok
+	}
+	return strings.Contains(lookup.Path, ":"+param)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func isPlaceholderSecret(secret string) bool {
+	lower := strings.ToLower(strings.TrimSpace(secret))
+	for _, placeholder := range placeholderSecrets {
+		if lower == placeholder {
+			return true
+		}
+	}
+	return strings.Contains(lower, "change-in-production") || strings.Contains(lower, "changeme")
+}
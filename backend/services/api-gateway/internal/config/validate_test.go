@@ -0,0 +1,356 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validConfig returns a configuration that passes validation
+func validConfig() *Config {
+	service := ServiceConfig{
+		Host:     "localhost",
+		Port:     3001,
+		Timeout:  30 * time.Second,
+		Balancer: "round_robin",
+	}
+
+	cfg := &Config{}
+	cfg.Server.Port = 8080
+	cfg.Server.Mode = "production"
+	cfg.Services = ServicesConfig{
+		"invoice-service": service,
+		"tenant-service":  service,
+	}
+	cfg.Redis.Host = "localhost"
+	cfg.Redis.Port = 6379
+	cfg.JWT.Secret = "4f1c9a7e2b8d6035e1a4c7f9b2d8e6a1"
+	cfg.JWT.Expiration = 24 * time.Hour
+	cfg.JWT.Algorithms = []string{"HS256"}
+	cfg.JWT.Validation.Mode = "local"
+	cfg.JWT.Revocation.FailurePolicy = "closed"
+	cfg.Authorization.RolesClaim = "roles"
+	cfg.RateLimit.DefaultPolicy = "default"
+	cfg.RateLimit.FailureMode = "local"
+	cfg.RateLimit.Replicas = 1
+	cfg.RateLimit.Policies = map[string]RateLimitPolicyConfig{
+		"default": {Key: []string{"ip"}, Requests: 100, Period: time.Minute},
+	}
+	cfg.Login.IdentifierFields = []string{"identifier"}
+	cfg.Login.FailureStatuses = []int{401}
+	cfg.Login.Identifier = LoginPolicyConfig{Window: 15 * time.Minute, DelayAfter: 3, Delay: time.Second, MaxDelay: 30 * time.Second}
+	cfg.Login.IP = LoginPolicyConfig{Window: 15 * time.Minute, LockoutAfter: 100, Lockout: 15 * time.Minute}
+	cfg.Metrics.Enabled = true
+	cfg.Metrics.Path = "/metrics"
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(cfg *Config)
+		problems []string
+	}{
+		{
+			name:   "Valid configuration",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name: "Port out of range",
+			modify: func(cfg *Config) {
+				cfg.Server.Port = 0
+			},
+			problems: []string{"server.port: must be between 1 and 65535, got 0"},
+		},
+		{
+			name: "Empty service host",
+			modify: func(cfg *Config) {
+				service := cfg.Services["invoice-service"]
+				service.Host = ""
+				cfg.Services["invoice-service"] = service
+			},
+			problems: []string{"services.invoice-service.host: is required"},
+		},
+		{
+			name: "Endpoints replace host and port",
+			modify: func(cfg *Config) {
+				service := cfg.Services["tenant-service"]
+				service.Host = ""
+				service.Endpoints = []EndpointConfig{{Host: "tenant-0", Port: 70000}}
+				cfg.Services["tenant-service"] = service
+			},
+			problems: []string{"services.tenant-service.endpoints[0].port: must be between 1 and 65535, got 70000"},
+		},
+		{
+			name: "Concurrency limits and route priority",
+			modify: func(cfg *Config) {
+				service := cfg.Services["invoice-service"]
+				service.Concurrency = ConcurrencyConfig{
+					Enabled:          true,
+					InitialLimit:     500,
+					MinLimit:         10,
+					MaxLimit:         200,
+					Tolerance:        0.5,
+					Window:           time.Second,
+					LowPriorityShare: 0.5,
+				}
+				cfg.Services["invoice-service"] = service
+				cfg.Routes = []RouteConfig{{Method: "GET", Path: "/api/v1/reports", Service: "invoice-service", Priority: "background"}}
+			},
+			problems: []string{
+				"services.invoice-service.concurrency.initial_limit: must be between min_limit and max_limit, got 500",
+				"services.invoice-service.concurrency.tolerance: must be at least 1, got 0.5",
+				`routes[0].priority: must be one of critical, normal, low, got "background"`,
+			},
+		},
+		{
+			name: "Service name that is not env-safe",
+			modify: func(cfg *Config) {
+				cfg.Services["Tenant_Service"] = cfg.Services["tenant-service"]
+			},
+			problems: []string{"services.Tenant_Service: name must be lowercase letters, digits and hyphens"},
+		},
+		{
+			name: "Placeholder secret in production",
+			modify: func(cfg *Config) {
+				cfg.JWT.Secret = "your-secret-key-change-in-production"
+			},
+			problems: []string{"jwt.secret: is a placeholder value and must be replaced in production"},
+		},
+		{
+			name: "Placeholder secret allowed outside production",
+			modify: func(cfg *Config) {
+				cfg.Server.Mode = "debug"
+				cfg.JWT.Secret = "your-secret-key-change-in-production"
+			},
+		},
+		{
+			name: "Unknown algorithm and negative max age",
+			modify: func(cfg *Config) {
+				cfg.JWT.Algorithms = []string{"HS256", "none"}
+				cfg.JWT.MaxAge = -time.Hour
+			},
+			problems: []string{
+				`jwt.algorithms[1]: must be one of HS256, HS384, HS512, RS256, RS384, RS512, PS256, PS384, PS512, ES256, ES384, ES512, got "none"`,
+				"jwt.max_age: must not be negative, got -3600000000000",
+			},
+		},
+		{
+			name: "Asymmetric algorithms need keys",
+			modify: func(cfg *Config) {
+				cfg.JWT.Algorithms = []string{"RS256"}
+			},
+			problems: []string{"jwt.keys: pem, jwks_file or jwks_url is required for RS256"},
+		},
+		{
+			name: "Secret is not checked without HMAC algorithms",
+			modify: func(cfg *Config) {
+				cfg.JWT.Secret = ""
+				cfg.JWT.Algorithms = []string{"ES256"}
+				cfg.JWT.Keys = JWTKeysConfig{
+					JWKSURL:         "https://auth.baribhara.local/.well-known/jwks.json",
+					RefreshInterval: 5 * time.Minute,
+				}
+			},
+		},
+		{
+			name: "Remote token validation needs a gRPC service",
+			modify: func(cfg *Config) {
+				cfg.JWT.Validation = TokenValidationConfig{
+					Mode:          "remote",
+					Service:       "invoice-service",
+					Timeout:       time.Second,
+					FailurePolicy: "sometimes",
+				}
+			},
+			problems: []string{
+				"jwt.validation.service: invoice-service has no grpc_port",
+				`jwt.validation.failure_policy: must be one of open, closed, got "sometimes"`,
+			},
+		},
+		{
+			name: "gRPC bodies need a size limit",
+			modify: func(cfg *Config) {
+				service := cfg.Services["tenant-service"]
+				service.GRPCPort = 50054
+				cfg.Services["tenant-service"] = service
+			},
+			problems: []string{"services.tenant-service.grpc_max_body_bytes: must be positive, got 0"},
+		},
+		{
+			name: "Unknown revocation failure policy",
+			modify: func(cfg *Config) {
+				cfg.JWT.Revocation.FailurePolicy = "fail_open"
+			},
+			problems: []string{`jwt.revocation.failure_policy: must be one of open, closed, got "fail_open"`},
+		},
+		{
+			name: "Ownership lookups and rules",
+			modify: func(cfg *Config) {
+				cfg.Authorization.Ownership = map[string]OwnershipLookupConfig{
+					"tenant-invoices": {
+						Service:  "invoice-service",
+						Path:     "/api/v1/invoices/:resource_id",
+						Owner:    "tenant_id",
+						CacheTTL: time.Minute,
+					},
+					"caretaker-properties": {
+						Service: "property-service",
+						GRPC:    &GRPCRouteConfig{Method: "property.PropertyService/GetPropertiesByCaretaker"},
+						IDs:     "properties.id",
+					},
+				}
+				cfg.Routes = []RouteConfig{{
+					Method:  "GET",
+					Path:    "/api/v1/invoices/:id",
+					Service: "invoice-service",
+					Auth:    true,
+					Ownership: &OwnershipConfig{
+						Param: "id",
+						Rules: []OwnershipRuleConfig{{Roles: []string{"tenant"}, Lookup: "tenant-invoice"}},
+					},
+				}}
+			},
+			problems: []string{
+				`authorization.ownership.caretaker-properties.service: unknown service "property-service"`,
+				"authorization.ownership.caretaker-properties: ids lookups must pass user_id",
+				`routes[0].ownership.rules[0].lookup: unknown lookup "tenant-invoice"`,
+			},
+		},
+		{
+			name: "Route table",
+			modify: func(cfg *Config) {
+				cfg.Routes = []RouteConfig{
+					{Method: "FETCH", Path: "api/v1/invoices", Service: "invoice-service", Upstream: "invoices"},
+					{Method: "GET", Path: "/api/v1/reports", Service: "report-service", Timeout: time.Minute, MaxTimeout: time.Second},
+					{Method: "POST", Path: "/api/v1/auth/logout", Service: "invoice-service", Roles: []string{"admin"}, RevokeToken: true},
+					{
+						Method:    "GET",
+						Path:      "/api/v1/invoices/:id",
+						Service:   "invoice-service",
+						Auth:      true,
+						Ownership: &OwnershipConfig{Param: "invoice_id", Rules: []OwnershipRuleConfig{{Lookup: "tenant-invoice"}}},
+					},
+					{Method: "GET", Path: "/api/v1/tenants", Service: "tenant-service", Transport: "grpc"},
+					{Method: "get", Path: "/api/v1/tenants", Service: "tenant-service", Transport: "soap"},
+				}
+				cfg.Authorization.Ownership = map[string]OwnershipLookupConfig{
+					"tenant-invoice": {Service: "invoice-service", Path: "/internal/invoices/:resource_id/tenant", Owner: "tenant_user_id"},
+				}
+			},
+			problems: []string{
+				`routes[0].method: must be one of GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS, got "FETCH"`,
+				`routes[0].path: must start with /, got "api/v1/invoices"`,
+				`routes[0].upstream: must start with /, got "invoices"`,
+				`routes[1].service: unknown service "report-service"`,
+				"routes[1].max_timeout: must not be shorter than timeout 1m0s, got 1s",
+				"routes[2].roles: require auth",
+				"routes[2].revoke_token: requires auth",
+				`routes[3].ownership.param: "invoice_id" is not a parameter of /api/v1/invoices/:id`,
+				"routes[4].grpc.method: is required for grpc transport",
+				`routes[5].transport: must be one of http, grpc, got "soap"`,
+				"routes[5]: duplicate route GET /api/v1/tenants",
+			},
+		},
+		{
+			name: "Global rate limit keyed by user",
+			modify: func(cfg *Config) {
+				cfg.RateLimit.GlobalPolicy = "default"
+				cfg.RateLimit.Policies["default"] = RateLimitPolicyConfig{Key: []string{"ip", "user"}, Requests: 100, Period: time.Minute}
+			},
+			problems: []string{
+				`rate_limit.global_policy: policy "default" must not be keyed by user`,
+			},
+		},
+		{
+			name: "Unknown global rate limit",
+			modify: func(cfg *Config) {
+				cfg.RateLimit.GlobalPolicy = "global"
+			},
+			problems: []string{
+				`rate_limit.global_policy: unknown policy "global"`,
+			},
+		},
+		{
+			name: "Rate limit policies",
+			modify: func(cfg *Config) {
+				cfg.RateLimit.ExemptCIDRs = []string{"10.0.0.0/8", "10.0.0.1", "internal"}
+				cfg.RateLimit.Policies["login"] = RateLimitPolicyConfig{
+					Key:      []string{"ip", "phone"},
+					Requests: 5,
+					Period:   time.Minute,
+					Roles: map[string]RateLimitRoleConfig{
+						"admin": {Requests: 0, Period: time.Minute},
+					},
+				}
+				cfg.Routes = []RouteConfig{
+					{
+						Method:    "POST",
+						Path:      "/api/v1/reports/generate",
+						Service:   "invoice-service",
+						RateLimit: &RouteRateLimitConfig{Policy: "reports", Cost: 10},
+					},
+					{
+						Method:    "POST",
+						Path:      "/api/v1/auth/login",
+						Service:   "invoice-service",
+						RateLimit: &RouteRateLimitConfig{Policy: "login", Cost: 10},
+					},
+				}
+			},
+			problems: []string{
+				`rate_limit.exempt_cidrs[2]: must be an IP or CIDR, got "internal"`,
+				`rate_limit.policies.login.key[1]: must be one of ip, user, api_key, got "phone"`,
+				"rate_limit.policies.login.roles.admin.requests: must be positive, got 0",
+				`routes[0].rate_limit.policy: unknown policy "reports"`,
+				`routes[1].rate_limit.cost: 10 exceeds the burst of policy "login", 5`,
+			},
+		},
+		{
+			name: "Login protection",
+			modify: func(cfg *Config) {
+				cfg.Login.FailureStatuses = []int{401, 200}
+				cfg.Login.Identifier.MaxDelay = 0
+				cfg.Login.IP.Lockout = 0
+			},
+			problems: []string{
+				"login_protection.failure_statuses[1]: must be between 400 and 599, got 200",
+				"login_protection.identifier.max_delay: must not be shorter than delay 1s, got 0s",
+				"login_protection.ip.lockout: must be a positive duration, got 0s",
+			},
+		},
+		{
+			name: "Every problem is reported",
+			modify: func(cfg *Config) {
+				cfg.Server.Port = 0
+				cfg.Redis.Host = ""
+				cfg.JWT.Secret = "your-secret-key"
+			},
+			problems: []string{
+				"server.port: must be between 1 and 65535, got 0",
+				"redis.host: is required",
+				"jwt.secret: must be at least 32 characters, got 15",
+				"jwt.secret: is a placeholder value and must be replaced in production",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if tt.problems == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var invalid *ValidationError
+			require.ErrorAs(t, err, &invalid)
+			assert.Equal(t, tt.problems, invalid.Problems)
+		})
+	}
+}
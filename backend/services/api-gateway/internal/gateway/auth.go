@@ -0,0 +1,136 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+	"baribhara/api-gateway/internal/middleware"
+	"baribhara/api-gateway/pkg/client"
+	"baribhara/api-gateway/pkg/pb/authpb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Token validation modes and failure policies
+const (
+	validationRemote = "remote"
+	failOpen         = "open"
+)
+
+// authServiceValidator asks auth-service's AuthService.ValidateToken about a
+// token
+type authServiceValidator struct {
+	client  authpb.AuthServiceClient
+	timeout time.Duration
+	logger  *zap.Logger
+}
+
+// ValidateToken implements middleware.TokenValidator. Rejections of the token
+// itself are verdicts; anything else means auth-service could not answer.
+func (v *authServiceValidator) ValidateToken(ctx context.Context, token string) (*middleware.TokenVerdict, error) {
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+
+	resp, err := v.client.ValidateToken(ctx, &authpb.ValidateTokenRequest{Token: token})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unauthenticated, codes.PermissionDenied, codes.InvalidArgument, codes.NotFound:
+			return &middleware.TokenVerdict{Reason: status.Convert(err).Message()}, nil
+		}
+		v.logger.Warn("Token validation failed", zap.Error(err))
+		return nil, err
+	}
+
+	verdict := &middleware.TokenVerdict{
+		Valid:  resp.GetValid(),
+		UserID: resp.GetUserId(),
+		Role:   resp.GetRole(),
+		Reason: resp.GetError(),
+	}
+	if resp.GetExpiresAt() > 0 {
+		verdict.ExpiresAt = time.Unix(resp.GetExpiresAt(), 0)
+	}
+	return verdict, nil
+}
+
+// newTokenValidator builds the cached auth-service validator for remote token
+// validation, or returns nil in local mode
+func newTokenValidator(cfg config.TokenValidationConfig, clients *client.Manager, rdb *redis.Client, logger *zap.Logger) (*middleware.CachedTokenValidator, error) {
+	if cfg.Mode != validationRemote {
+		return nil, nil
+	}
+
+	conn, err := clients.GRPCConn(cfg.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	return middleware.NewCachedTokenValidator(&authServiceValidator{
+		client:  authpb.NewAuthServiceClient(conn),
+		timeout: cfg.Timeout,
+		logger:  logger,
+	}, rdb, cfg.CacheTTL), nil
+}
+
+// keyLoadTimeout bounds the initial load of the JWT key set
+const keyLoadTimeout = 10 * time.Second
+
+// newKeySet loads the public keys of asymmetric tokens and keeps them fresh,
+// or returns nil when no key source is configured
+func newKeySet(cfg config.JWTKeysConfig, logger *zap.Logger) (*middleware.KeySet, error) {
+	if !cfg.Configured() {
+		return nil, nil
+	}
+
+	pemKeys := make([]middleware.PEMKey, 0, len(cfg.PEM))
+	for _, key := range cfg.PEM {
+		pemKeys = append(pemKeys, middleware.PEMKey{KID: key.KID, File: key.File})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keyLoadTimeout)
+	defer cancel()
+	keys, err := middleware.NewKeySet(ctx, middleware.KeySetOptions{
+		PEM:         pemKeys,
+		JWKSFile:    cfg.JWKSFile,
+		JWKSURL:     cfg.JWKSURL,
+		GracePeriod: cfg.GracePeriod,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("jwt.keys: %w", err)
+	}
+
+	keys.Start(cfg.RefreshInterval, func(err error) {
+		logger.Warn("JWT key refresh failed, keeping current keys", zap.Error(err))
+	})
+	return keys, nil
+}
+
+// authMiddleware authenticates routes marked auth
+func (g *Gateway) authMiddleware() gin.HandlerFunc {
+	jwtConfig := g.config.JWT
+	verifier := middleware.NewJWTVerifier(jwtConfig.Secret, middleware.JWTOptions{
+		Keys:             g.keys,
+		Denylist:         g.denylist,
+		DenylistFailOpen: jwtConfig.Revocation.FailurePolicy == failOpen,
+		Logger:           g.logger,
+		RolesClaim:       g.config.Authorization.RolesClaim,
+		Hierarchy:        middleware.NewRoleHierarchy(g.config.Authorization.RoleHierarchy),
+		Algorithms:       jwtConfig.Algorithms,
+		Issuer:           jwtConfig.Issuer,
+		Audience:         jwtConfig.Audience,
+		RequiredClaims:   jwtConfig.RequiredClaims,
+		Leeway:           jwtConfig.Leeway,
+		MaxAge:           jwtConfig.MaxAge,
+	})
+
+	if g.tokenValidator == nil {
+		return middleware.JWTAuth(verifier)
+	}
+	return middleware.RemoteJWTAuth(verifier, g.tokenValidator, jwtConfig.Validation.FailurePolicy == failOpen)
+}
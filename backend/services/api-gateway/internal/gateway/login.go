@@ -0,0 +1,30 @@
+package gateway
+
+import (
+	"baribhara/api-gateway/internal/config"
+	"baribhara/api-gateway/internal/middleware"
+
+	"go.uber.org/zap"
+)
+
+// newLoginGuard builds the guard of routes with login_protection
+func newLoginGuard(cfg config.LoginConfig, attempts middleware.LoginAttempts, logger *zap.Logger) *middleware.LoginGuard {
+	return middleware.NewLoginGuard(attempts, middleware.LoginGuardOptions{
+		IdentifierFields: cfg.IdentifierFields,
+		FailureStatuses:  cfg.FailureStatuses,
+		Identifier:       loginPolicy(cfg.Identifier),
+		IP:               loginPolicy(cfg.IP),
+		Logger:           logger,
+	})
+}
+
+func loginPolicy(cfg config.LoginPolicyConfig) middleware.LoginPolicy {
+	return middleware.LoginPolicy{
+		Window:       cfg.Window,
+		DelayAfter:   cfg.DelayAfter,
+		Delay:        cfg.Delay,
+		MaxDelay:     cfg.MaxDelay,
+		LockoutAfter: cfg.LockoutAfter,
+		Lockout:      cfg.Lockout,
+	}
+}
@@ -0,0 +1,54 @@
+package gateway
+
+import (
+	"fmt"
+	"reflect"
+
+	"baribhara/api-gateway/internal/config"
+	"baribhara/api-gateway/internal/middleware"
+	"baribhara/api-gateway/pkg/client"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newOwnershipLookups builds the configured ownership lookups by name. A lookup
+// whose settings are unchanged from previous keeps its cache.
+func newOwnershipLookups(cfg map[string]config.OwnershipLookupConfig, clients *client.Manager, previous *Gateway) (map[string]*middleware.OwnershipLookup, error) {
+	lookups := make(map[string]*middleware.OwnershipLookup, len(cfg))
+
+	for name, lookupConfig := range cfg {
+		var fetcher *client.Lookup
+		var err error
+		if lookupConfig.GRPC != nil && lookupConfig.GRPC.Method != "" {
+			fetcher, err = clients.NewGRPCLookup(lookupConfig.Service, lookupConfig.GRPC.Method, lookupConfig.GRPC.Params)
+		} else {
+			fetcher, err = clients.NewHTTPLookup(lookupConfig.Service, lookupConfig.Path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("authorization.ownership.%s: %w", name, err)
+		}
+
+		lookup := middleware.NewOwnershipLookup(name, fetcher, lookupConfig.IDs, lookupConfig.Owner, lookupConfig.CacheTTL)
+		if previous != nil {
+			if previousConfig, ok := previous.config.Authorization.Ownership[name]; ok && reflect.DeepEqual(previousConfig, lookupConfig) {
+				lookup.ShareCache(previous.ownershipLookups[name])
+			}
+		}
+		lookups[name] = lookup
+	}
+
+	return lookups, nil
+}
+
+// requireOwnership enforces a route's ownership rules
+func (g *Gateway) requireOwnership(route config.RouteConfig) gin.HandlerFunc {
+	rules := make([]middleware.OwnershipRule, 0, len(route.Ownership.Rules))
+	for _, rule := range route.Ownership.Rules {
+		rules = append(rules, middleware.OwnershipRule{
+			Roles:  rule.Roles,
+			Lookup: g.ownershipLookups[rule.Lookup],
+		})
+	}
+
+	return middleware.RequireOwnership(route.Ownership.Param, route.Ownership.BypassRoles, rules)
+}
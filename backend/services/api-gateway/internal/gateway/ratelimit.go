@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"reflect"
+
+	"baribhara/api-gateway/internal/config"
+	"baribhara/api-gateway/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// rateLimiterOptions returns the rate limiter settings of cfg
+func rateLimiterOptions(cfg config.RateLimitConfig, logger *zap.Logger) middleware.RateLimiterOptions {
+	return middleware.RateLimiterOptions{
+		ExemptCIDRs:  cfg.ExemptCIDRs,
+		APIKeyHeader: cfg.APIKeyHeader,
+		FailureMode:  cfg.FailureMode,
+		Replicas:     cfg.Replicas,
+		Logger:       logger,
+	}
+}
+
+// newRateLimiter builds the rate limiter, keeping the one of previous, with
+// its fallback buckets, when neither its settings nor Redis changed
+func newRateLimiter(cfg config.RateLimitConfig, rdb *sharedRedis, logger *zap.Logger, previous *Gateway) (*middleware.RateLimiter, error) {
+	opts := rateLimiterOptions(cfg, logger)
+	if previous != nil && previous.redis == rdb &&
+		reflect.DeepEqual(rateLimiterOptions(previous.config.RateLimit, previous.logger), opts) {
+		return previous.rateLimiter, nil
+	}
+
+	return middleware.NewRateLimiter(middleware.NewRedisLimiter(rdb.Client), opts)
+}
+
+// newRateLimitPolicies builds the configured rate limit policies by name
+func newRateLimitPolicies(cfg config.RateLimitConfig) map[string]*middleware.RateLimitPolicy {
+	policies := make(map[string]*middleware.RateLimitPolicy, len(cfg.Policies))
+
+	for name, policyConfig := range cfg.Policies {
+		policy := &middleware.RateLimitPolicy{
+			Name: name,
+			Key:  policyConfig.Key,
+			Limit: middleware.Limit{
+				Requests: policyConfig.Requests,
+				Period:   policyConfig.Period,
+				Burst:    policyConfig.Burst,
+			},
+			Roles: make(map[string]middleware.Limit, len(policyConfig.Roles)),
+		}
+		for role, limit := range policyConfig.Roles {
+			policy.Roles[role] = middleware.Limit{
+				Requests: limit.Requests,
+				Period:   limit.Period,
+				Burst:    limit.Burst,
+			}
+		}
+		policies[name] = policy
+	}
+
+	return policies
+}
+
+// rateLimit counts a route's requests against its policy, or the default one
+func (g *Gateway) rateLimit(route config.RouteConfig) gin.HandlerFunc {
+	name, cost := g.config.RateLimit.DefaultPolicy, 1
+	if route.RateLimit != nil {
+		if route.RateLimit.Policy != "" {
+			name = route.RateLimit.Policy
+		}
+		cost = route.RateLimit.Cost
+	}
+
+	return g.rateLimiter.Limit(g.rateLimitPolicies[name], cost)
+}
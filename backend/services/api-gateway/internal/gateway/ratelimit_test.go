@@ -0,0 +1,95 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGlobalRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+
+	// Redis is unreachable, so the limits are kept in memory
+	cfg := testConfig(t, upstream, "/api/v1/tenants")
+	cfg.Routes[0].Auth = true
+	cfg.RateLimit.FailureMode = "local"
+	cfg.RateLimit.GlobalPolicy = "global"
+	cfg.RateLimit.Policies["global"] = config.RateLimitPolicyConfig{Key: []string{"ip"}, Requests: 3, Period: time.Minute}
+	gw, err := NewGateway(cfg, zap.NewNop())
+	require.NoError(t, err)
+	defer gw.Close()
+	router, err := gw.SetupRoutes()
+	require.NoError(t, err)
+
+	request := func(path string) int {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer not-a-token")
+		req.RemoteAddr = "203.0.113.7:4000"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Requests failing auth count, as do those matching no route
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusUnauthorized, request("/api/v1/tenants"))
+	}
+	assert.Equal(t, http.StatusNotFound, request("/api/v1/leases"))
+	assert.Equal(t, http.StatusTooManyRequests, request("/api/v1/tenants"))
+	assert.Equal(t, http.StatusTooManyRequests, request("/api/v1/leases"))
+
+	// Health checks are not limited
+	assert.NotEqual(t, http.StatusTooManyRequests, request("/health"))
+}
+
+func TestTrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+
+	cfg := testConfig(t, upstream, "/api/v1/tenants")
+	cfg.Server.TrustedProxies = []string{"10.0.0.0/8"}
+	cfg.RateLimit.FailureMode = "local"
+	cfg.RateLimit.GlobalPolicy = "global"
+	cfg.RateLimit.Policies["global"] = config.RateLimitPolicyConfig{Key: []string{"ip"}, Requests: 1, Period: time.Minute}
+	gw, err := NewGateway(cfg, zap.NewNop())
+	require.NoError(t, err)
+	defer gw.Close()
+	router, err := gw.SetupRoutes()
+	require.NoError(t, err)
+
+	request := func(remoteAddr, forwardedFor string) int {
+		req, _ := http.NewRequest("GET", "/api/v1/tenants", nil)
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Clients behind the ingress are told apart by X-Forwarded-For
+	assert.Equal(t, http.StatusOK, request("10.1.2.3:4000", "198.51.100.1"))
+	assert.Equal(t, http.StatusOK, request("10.1.2.3:4000", "198.51.100.2"))
+	assert.Equal(t, http.StatusTooManyRequests, request("10.1.2.3:4000", "198.51.100.1"))
+
+	// Anyone else's X-Forwarded-For is ignored
+	assert.Equal(t, http.StatusOK, request("203.0.113.7:4000", "198.51.100.3"))
+	assert.Equal(t, http.StatusTooManyRequests, request("203.0.113.7:4000", "198.51.100.4"))
+
+	// Proxies that do not parse keep the gateway from starting
+	cfg.Server.TrustedProxies = []string{"ingress"}
+	_, err = NewHandler(gw, zap.NewNop())
+	assert.ErrorContains(t, err, "server.trusted_proxies")
+}
@@ -0,0 +1,40 @@
+package gateway
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// sharedRedis is a Redis client that gateways built from the same Redis
+// settings share across reloads. The last gateway to drop it closes it.
+type sharedRedis struct {
+	*redis.Client
+	refs atomic.Int32
+}
+
+func newSharedRedis(cfg config.RedisConfig) *sharedRedis {
+	r := &sharedRedis{Client: redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})}
+	r.refs.Store(1)
+	return r
+}
+
+// hold shares the client with another gateway
+func (r *sharedRedis) hold() *sharedRedis {
+	r.refs.Add(1)
+	return r
+}
+
+// drop releases a gateway's hold, closing the client after the last one
+func (r *sharedRedis) drop() {
+	if r.refs.Add(-1) == 0 {
+		r.Client.Close()
+	}
+}
@@ -0,0 +1,143 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// drainGrace is how long past the slowest request's deadline a replaced
+// gateway is kept, for it to write its response
+const drainGrace = 5 * time.Second
+
+// restartKeys are settings the running HTTP server cannot pick up on reload
+var restartKeys = []string{
+	"server.port",
+	"server.mode",
+	"server.read_timeout",
+	"server.write_timeout",
+	"server.idle_timeout",
+}
+
+// generation is one gateway built from one configuration, with its router
+type generation struct {
+	gateway *Gateway
+	router  *gin.Engine
+}
+
+// Handler serves requests with the current gateway. Reload builds a complete
+// new gateway from a new configuration and swaps it in atomically, so
+// in-flight requests finish on the gateway that accepted them. Services whose
+// settings did not change keep their upstream state across the swap.
+type Handler struct {
+	logger  *zap.Logger
+	mu      sync.Mutex
+	current atomic.Pointer[generation]
+}
+
+// NewHandler creates a handler serving the given gateway
+func NewHandler(gw *Gateway, logger *zap.Logger) (*Handler, error) {
+	router, err := gw.SetupRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Handler{logger: logger}
+	h.current.Store(&generation{gateway: gw, router: router})
+	return h, nil
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.current.Load().router.ServeHTTP(w, r)
+}
+
+// Config returns the configuration currently being served
+func (h *Handler) Config() *config.Config {
+	return h.current.Load().gateway.config
+}
+
+// Reload swaps in a gateway built from cfg. If the new configuration cannot be
+// built, the running gateway is kept and the error is returned.
+func (h *Handler) Reload(cfg *config.Config) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	old := h.current.Load()
+	changes := config.Diff(old.gateway.config, cfg)
+	if len(changes) == 0 {
+		h.logger.Info("Configuration reloaded, nothing changed")
+		return nil
+	}
+
+	next, err := h.build(cfg, old.gateway)
+	if err != nil {
+		h.logger.Error("Configuration reload rejected, keeping current configuration", zap.Error(err))
+		return err
+	}
+
+	next.gateway.clients.Activate()
+	h.current.Store(next)
+	time.AfterFunc(old.gateway.drainPeriod(), old.gateway.Close)
+
+	h.logger.Info("Configuration reloaded", zap.Strings("changes", changes))
+	for _, change := range changes {
+		for _, key := range restartKeys {
+			if strings.HasPrefix(change, key+":") {
+				h.logger.Warn("Setting changed but requires a restart", zap.String("change", change))
+			}
+		}
+	}
+
+	return nil
+}
+
+// build creates a gateway to replace previous and its router, turning router
+// panics from bad route definitions into errors
+func (h *Handler) build(cfg *config.Config, previous *Gateway) (next *generation, err error) {
+	gw, err := newGateway(cfg, h.logger, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			gw.Close()
+			next, err = nil, fmt.Errorf("invalid routes: %v", r)
+		}
+	}()
+
+	router, err := gw.SetupRoutes()
+	if err != nil {
+		gw.Close()
+		return nil, err
+	}
+	return &generation{gateway: gw, router: router}, nil
+}
+
+// drainPeriod is how long the gateway keeps its health checkers and Redis
+// connection once replaced, so requests it already accepted can finish: the
+// longest any of them may run, plus a grace period
+func (g *Gateway) drainPeriod() time.Duration {
+	longest := time.Duration(g.config.Server.WriteTimeout) * time.Second
+	for _, route := range g.config.Routes {
+		longest = max(longest, g.routeTimeout(route), route.MaxTimeout)
+	}
+	return longest + drainGrace
+}
+
+// Close releases the resources of the gateway currently being served
+func (h *Handler) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.current.Load().gateway.Close()
+}
@@ -0,0 +1,181 @@
+package gateway
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// testConfig routes the given paths to a tenant service at upstream
+func testConfig(t *testing.T, upstream *httptest.Server, paths ...string) *config.Config {
+	u, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	host, portStr, err := net.SplitHostPort(u.Host)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	cfg := &config.Config{}
+	cfg.Redis.Host = "127.0.0.1"
+	cfg.Redis.Port = 1
+	cfg.RateLimit.DefaultPolicy = "default"
+	cfg.RateLimit.FailureMode = "open"
+	cfg.RateLimit.Policies = map[string]config.RateLimitPolicyConfig{
+		"default": {Key: []string{"ip"}, Requests: 100, Period: time.Minute},
+	}
+	cfg.Services = config.ServicesConfig{
+		"tenant-service": {
+			Host:    host,
+			Port:    port,
+			Timeout: 5 * time.Second,
+			Transport: config.TransportConfig{
+				DialTimeout: time.Second,
+			},
+		},
+	}
+	for _, path := range paths {
+		cfg.Routes = append(cfg.Routes, config.RouteConfig{
+			Method:   http.MethodGet,
+			Path:     path,
+			Service:  "tenant-service",
+			Upstream: path,
+		})
+	}
+	return cfg
+}
+
+func TestHandlerReload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	gw, err := NewGateway(testConfig(t, upstream, "/api/v1/tenants"), zap.NewNop())
+	require.NoError(t, err)
+	handler, err := NewHandler(gw, zap.NewNop())
+	require.NoError(t, err)
+	defer handler.Close()
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	status := func(path string) int {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, status("/api/v1/tenants"))
+	assert.Equal(t, http.StatusNotFound, status("/api/v1/leases"))
+
+	// A new route table takes effect without restarting the server
+	require.NoError(t, handler.Reload(testConfig(t, upstream, "/api/v1/tenants", "/api/v1/leases")))
+	assert.Equal(t, http.StatusOK, status("/api/v1/leases"))
+
+	// An invalid configuration is rejected and the current one kept
+	bad := testConfig(t, upstream, "/api/v1/tenants")
+	bad.Routes[0].Transport = "grpc"
+	bad.Routes[0].GRPC = &config.GRPCRouteConfig{Method: "tenant.TenantService/Missing"}
+	assert.Error(t, handler.Reload(bad))
+	assert.Equal(t, http.StatusOK, status("/api/v1/leases"))
+
+	// Conflicting routes make gin panic, which is also a rejected reload
+	conflict := testConfig(t, upstream, "/api/v1/tenants/:id", "/api/v1/tenants/:name/leases")
+	assert.Error(t, handler.Reload(conflict))
+	assert.Equal(t, http.StatusOK, status("/api/v1/leases"))
+}
+
+func TestReloadCarriesOver(t *testing.T) {
+	var ownerCalls atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ownerCalls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ids": ["property-1"]}`))
+	}))
+	defer upstream.Close()
+
+	newConfig := func(paths ...string) *config.Config {
+		cfg := testConfig(t, upstream, paths...)
+		cfg.Authorization.Ownership = map[string]config.OwnershipLookupConfig{
+			"properties": {Service: "tenant-service", Path: "/owners/:user_id", IDs: "ids", CacheTTL: time.Minute},
+		}
+		return cfg
+	}
+
+	gw, err := NewGateway(newConfig("/api/v1/tenants"), zap.NewNop())
+	require.NoError(t, err)
+	owns, err := gw.ownershipLookups["properties"].Owns(context.Background(), "user-1", "property-1", http.Header{})
+	require.NoError(t, err)
+	assert.True(t, owns)
+
+	// A new route table keeps Redis, the rate limiter and the lookup caches
+	next, err := newGateway(newConfig("/api/v1/tenants", "/api/v1/leases"), zap.NewNop(), gw)
+	require.NoError(t, err)
+	defer next.Close()
+	assert.Same(t, gw.redis, next.redis)
+	assert.Same(t, gw.rateLimiter, next.rateLimiter)
+	owns, err = next.ownershipLookups["properties"].Owns(context.Background(), "user-1", "property-1", http.Header{})
+	require.NoError(t, err)
+	assert.True(t, owns)
+	assert.Equal(t, int32(1), ownerCalls.Load(), "served from the carried over cache")
+
+	// Closing the old gateway leaves the shared Redis client open
+	gw.Close()
+	assert.NotErrorIs(t, next.redis.Ping(context.Background()).Err(), redis.ErrClosed)
+
+	// Changed settings get their own
+	cfg := newConfig("/api/v1/tenants")
+	cfg.RateLimit.FailureMode = "closed"
+	cfg.Authorization.Ownership["properties"] = config.OwnershipLookupConfig{
+		Service: "tenant-service", Path: "/owners/:user_id", IDs: "ids", CacheTTL: 2 * time.Minute,
+	}
+	changed, err := newGateway(cfg, zap.NewNop(), next)
+	require.NoError(t, err)
+	defer changed.Close()
+	assert.Same(t, next.redis, changed.redis)
+	assert.NotSame(t, next.rateLimiter, changed.rateLimiter)
+	_, err = changed.ownershipLookups["properties"].Owns(context.Background(), "user-1", "property-1", http.Header{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), ownerCalls.Load())
+
+	cfg = newConfig("/api/v1/tenants")
+	cfg.Redis.Port = 2
+	moved, err := newGateway(cfg, zap.NewNop(), changed)
+	require.NoError(t, err)
+	defer moved.Close()
+	assert.NotSame(t, changed.redis, moved.redis)
+	assert.NotSame(t, changed.rateLimiter, moved.rateLimiter)
+}
+
+func TestDrainPeriod(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+
+	cfg := testConfig(t, upstream, "/api/v1/tenants", "/api/v1/leases", "/api/v1/reports")
+	cfg.Server.WriteTimeout = 1
+	gw, err := NewGateway(cfg, zap.NewNop())
+	require.NoError(t, err)
+	defer gw.Close()
+	assert.Equal(t, 5*time.Second+drainGrace, gw.drainPeriod(), "routes default to the service timeout")
+
+	cfg.Routes[1].Timeout = 20 * time.Second
+	cfg.Routes[2].MaxTimeout = 10 * time.Minute
+	assert.Equal(t, 10*time.Minute+drainGrace, gw.drainPeriod())
+}
@@ -0,0 +1,148 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+	"baribhara/api-gateway/internal/middleware"
+	"baribhara/api-gateway/pkg/client"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// transportGRPC marks routes proxied to a gRPC method
+const transportGRPC = "grpc"
+
+// registerRoutes builds gin routes from the configured route table
+func (g *Gateway) registerRoutes(router *gin.Engine) {
+	auth := g.authMiddleware()
+	for _, route := range g.config.Routes {
+		handlers := []gin.HandlerFunc{}
+		if route.Auth {
+			handlers = append(handlers, auth)
+		}
+		// After auth, so policies can key on the user and their roles
+		handlers = append(handlers, g.rateLimit(route))
+		if roles := routeRoles(route); len(roles) > 0 {
+			handlers = append(handlers, middleware.RequireAnyRole(roles...))
+		}
+		if route.RevokeToken {
+			handlers = append(handlers, middleware.RevokeOnSuccess(g.denylist))
+		}
+		if route.LoginProtection {
+			handlers = append(handlers, g.loginGuard.Protect())
+		}
+		handlers = append(handlers, middleware.IdentityHeaders(g.config.Identity.SigningSecret))
+		handlers = append(handlers, middleware.RequestTimeout(g.routeTimeout(route), route.MaxTimeout))
+		if route.Ownership != nil {
+			// After the identity headers, which lookups pass on upstream, and
+			// within the route's deadline
+			handlers = append(handlers, g.requireOwnership(route))
+		}
+		handlers = append(handlers, g.proxyRoute(route))
+
+		router.Handle(strings.ToUpper(route.Method), route.Path, handlers...)
+	}
+
+	g.logger.Info("Registered routes", zap.Int("count", len(g.config.Routes)))
+}
+
+// routeRoles returns the roles a route admits, from role and roles
+func routeRoles(route config.RouteConfig) []string {
+	if route.Role == "" {
+		return route.Roles
+	}
+	return append([]string{route.Role}, route.Roles...)
+}
+
+// routeTimeout returns the route's timeout, defaulting to its service's
+func (g *Gateway) routeTimeout(route config.RouteConfig) time.Duration {
+	if route.Timeout > 0 {
+		return route.Timeout
+	}
+	return g.clients.GetClient(route.Service).Config().Timeout
+}
+
+// proxyRoute returns a handler that proxies a request to the route's upstream
+func (g *Gateway) proxyRoute(route config.RouteConfig) gin.HandlerFunc {
+	upstream := route.Upstream
+	if upstream == "" {
+		upstream = route.Path
+	}
+
+	routeClient := g.clients.GetClient(route.Service).WithRouteHeaders(route.Headers).WithPriority(route.Priority)
+	if route.CircuitBreaker != nil && route.CircuitBreaker.Enabled {
+		name := route.Service + " " + strings.ToUpper(route.Method) + " " + route.Path
+		routeClient = routeClient.WithCircuitBreaker(g.clients.NewCircuitBreaker(name, *route.CircuitBreaker))
+	}
+
+	if method, ok := g.grpcMethods[routeKey(route)]; ok {
+		return func(c *gin.Context) {
+			routeClient.ProxyGRPC(c, method)
+		}
+	}
+
+	return func(c *gin.Context) {
+		routeClient.ProxyRequest(c, expandPath(upstream, c.Params))
+	}
+}
+
+// resolveGRPCMethods looks up the gRPC method of every grpc route, mapping each
+// path parameter to the request field of the same name unless grpc.params
+// says otherwise
+func resolveGRPCMethods(routes []config.RouteConfig, clients *client.Manager) (map[string]*client.GRPCMethod, error) {
+	methods := make(map[string]*client.GRPCMethod)
+
+	for i, route := range routes {
+		if route.Transport != transportGRPC {
+			continue
+		}
+
+		params := make(map[string]string)
+		for _, name := range route.PathParams() {
+			params[name] = name
+			if field, ok := route.GRPC.Params[strings.ToLower(name)]; ok {
+				params[name] = field
+			}
+		}
+
+		method, err := clients.NewGRPCMethod(route.Service, route.GRPC.Method, params)
+		if err != nil {
+			return nil, fmt.Errorf("routes[%d]: %w", i, err)
+		}
+		methods[routeKey(route)] = method
+	}
+
+	return methods, nil
+}
+
+// routeKey identifies a route by method and path
+func routeKey(route config.RouteConfig) string {
+	return strings.ToUpper(route.Method) + " " + route.Path
+}
+
+// expandPath fills ":name" and "*name" segments of an upstream path template
+// with the matching parameters of the incoming request
+func expandPath(template string, params gin.Params) string {
+	if !strings.ContainsAny(template, ":*") {
+		return template
+	}
+
+	segments := strings.Split(template, "/")
+	for i, segment := range segments {
+		if len(segment) < 2 || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		value := params.ByName(segment[1:])
+		if segment[0] == '*' {
+			// Catch-all parameters already carry their leading slash
+			value = strings.TrimPrefix(value, "/")
+		}
+		segments[i] = value
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,47 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		params   gin.Params
+		expected string
+	}{
+		{
+			name:     "Static path",
+			template: "/api/v1/properties/search",
+			expected: "/api/v1/properties/search",
+		},
+		{
+			name:     "Single parameter",
+			template: "/api/v1/tenants/:id",
+			params:   gin.Params{{Key: "id", Value: "42"}},
+			expected: "/api/v1/tenants/42",
+		},
+		{
+			name:     "Parameter in the middle",
+			template: "/api/v1/invoices/:id/pay",
+			params:   gin.Params{{Key: "id", Value: "inv-7"}},
+			expected: "/api/v1/invoices/inv-7/pay",
+		},
+		{
+			name:     "Catch-all parameter",
+			template: "/files/*path",
+			params:   gin.Params{{Key: "path", Value: "/a/b.pdf"}},
+			expected: "/files/a/b.pdf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, expandPath(tt.template, tt.params))
+		})
+	}
+}
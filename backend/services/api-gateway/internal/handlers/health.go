@@ -16,3 +16,33 @@ func Health(c *gin.Context) {
 		"version":   "1.0.0",
 	})
 }
+
+// UpstreamReporter exposes the circuit breaker state of upstream services
+type UpstreamReporter interface {
+	CircuitStates() map[string]string
+}
+
+// HealthWithUpstreams handles health check requests and includes the circuit
+// breaker state of every upstream. An open circuit marks the gateway as
+// degraded but still answers 200 so liveness probes don't restart it.
+func HealthWithUpstreams(upstreams UpstreamReporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		states := upstreams.CircuitStates()
+
+		status := "ok"
+		for _, state := range states {
+			if state != "closed" {
+				status = "degraded"
+				break
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":           status,
+			"timestamp":        time.Now().UTC().Format(time.RFC3339),
+			"service":          "api-gateway-go",
+			"version":          "1.0.0",
+			"circuit_breakers": states,
+		})
+	}
+}
@@ -26,3 +26,43 @@ func TestHealth(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "api-gateway-go")
 	assert.Contains(t, w.Body.String(), "1.0.0")
 }
+
+type stubUpstreams map[string]string
+
+func (s stubUpstreams) CircuitStates() map[string]string { return s }
+
+func TestHealthWithUpstreams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		states         stubUpstreams
+		expectedStatus string
+	}{
+		{
+			name:           "All circuits closed",
+			states:         stubUpstreams{"invoice-service": "closed"},
+			expectedStatus: `"status":"ok"`,
+		},
+		{
+			name:           "Open circuit",
+			states:         stubUpstreams{"invoice-service": "open", "auth-service": "closed"},
+			expectedStatus: `"status":"degraded"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/health", HealthWithUpstreams(tt.states))
+
+			req, _ := http.NewRequest("GET", "/health", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedStatus)
+			assert.Contains(t, w.Body.String(), `"invoice-service":"`+tt.states["invoice-service"]+`"`)
+		})
+	}
+}
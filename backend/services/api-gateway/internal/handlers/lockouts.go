@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"baribhara/api-gateway/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LockoutManager lists and lifts login lockouts
+type LockoutManager interface {
+	Lockouts(ctx context.Context) ([]middleware.LoginLockout, error)
+	Unlock(ctx context.Context, subject middleware.LoginSubject) (bool, error)
+}
+
+// ListLoginLockouts lists the identifiers and IPs locked out of logging in
+func ListLoginLockouts(manager LockoutManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lockouts, err := manager.Lockouts(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list lockouts", "code": "LOCKOUTS_UNAVAILABLE"})
+			return
+		}
+		if lockouts == nil {
+			lockouts = []middleware.LoginLockout{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"lockouts": lockouts})
+	}
+}
+
+// ClearLoginLockout lifts the lockout of the subject named by the :kind
+// parameter, identifier or ip, and the :value parameter, and forgets its
+// failed logins
+func ClearLoginLockout(manager LockoutManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kind := c.Param("kind")
+		if kind != middleware.LoginSubjectIdentifier && kind != middleware.LoginSubjectIP {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Kind must be identifier or ip", "code": "INVALID_REQUEST"})
+			return
+		}
+
+		subject := middleware.NewLoginSubject(kind, c.Param("value"))
+		unlocked, err := manager.Unlock(c.Request.Context(), subject)
+		if err != nil {
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear lockout", "code": "UNLOCK_FAILED"})
+			return
+		}
+		if !unlocked {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Not locked out", "code": "LOCKOUT_NOT_FOUND"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"kind": subject.Kind, "value": subject.Value, "unlocked": true})
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"baribhara/api-gateway/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLockouts keeps lockouts in memory
+type fakeLockouts struct {
+	lockouts map[middleware.LoginSubject]time.Time
+	err      error
+}
+
+func (f *fakeLockouts) Lockouts(ctx context.Context) ([]middleware.LoginLockout, error) {
+	var lockouts []middleware.LoginLockout
+	for subject, until := range f.lockouts {
+		lockouts = append(lockouts, middleware.LoginLockout{LoginSubject: subject, LockedUntil: until})
+	}
+	return lockouts, f.err
+}
+
+func (f *fakeLockouts) Unlock(ctx context.Context, subject middleware.LoginSubject) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	_, ok := f.lockouts[subject]
+	delete(f.lockouts, subject)
+	return ok, nil
+}
+
+func TestLoginLockouts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	lockouts := &fakeLockouts{lockouts: map[middleware.LoginSubject]time.Time{
+		{Kind: "identifier", Value: "alice@example.com"}: time.Date(2026, 10, 16, 12, 15, 0, 0, time.UTC),
+	}}
+	router := gin.New()
+	router.GET("/login-lockouts", ListLoginLockouts(lockouts))
+	router.DELETE("/login-lockouts/:kind/:value", ClearLoginLockout(lockouts))
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("GET", "/login-lockouts")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"lockouts": [{"kind": "identifier", "value": "alice@example.com", "locked_until": "2026-10-16T12:15:00Z"}]}`, w.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, request("DELETE", "/login-lockouts/user/alice").Code)
+	assert.Equal(t, http.StatusNotFound, request("DELETE", "/login-lockouts/ip/203.0.113.7").Code)
+	assert.Equal(t, http.StatusOK, request("DELETE", "/login-lockouts/identifier/Alice@Example.com").Code)
+
+	w = request("GET", "/login-lockouts")
+	assert.JSONEq(t, `{"lockouts": []}`, w.Body.String())
+
+	lockouts.err = errors.New("connection refused")
+	assert.Equal(t, http.StatusInternalServerError, request("GET", "/login-lockouts").Code)
+	assert.Equal(t, http.StatusInternalServerError, request("DELETE", "/login-lockouts/ip/203.0.113.7").Code)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserRevoker revokes every token of a user issued before a point in time
+type UserRevoker interface {
+	RevokeUser(ctx context.Context, userID string, before time.Time) error
+}
+
+// revokeTokensRequest is the optional body of RevokeUserTokens
+type revokeTokensRequest struct {
+	Before *time.Time `json:"before"`
+}
+
+// RevokeUserTokens revokes all tokens of the user named by the :id parameter
+// that were issued before the "before" time in the body, or before now. Token
+// issue times have whole seconds, so the cutoff is the start of its second,
+// as the response reports. A later time is refused, as it would revoke
+// tokens not issued yet.
+func RevokeUserTokens(revoker UserRevoker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req revokeTokensRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "code": "INVALID_REQUEST"})
+				return
+			}
+		}
+
+		before := time.Now()
+		if req.Before != nil {
+			if req.Before.After(before) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "before must not be in the future", "code": "INVALID_CUTOFF"})
+				return
+			}
+			before = *req.Before
+		}
+
+		userID := c.Param("id")
+		if err := revoker.RevokeUser(c.Request.Context(), userID, before); err != nil {
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke tokens", "code": "REVOCATION_FAILED"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"user_id":        userID,
+			"revoked_before": before.UTC().Format(time.RFC3339),
+		})
+	}
+}
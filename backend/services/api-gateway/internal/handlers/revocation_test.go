@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRevoker records the cutoff it was asked for
+type fakeRevoker struct {
+	userID string
+	before time.Time
+	err    error
+}
+
+func (f *fakeRevoker) RevokeUser(ctx context.Context, userID string, before time.Time) error {
+	f.userID, f.before = userID, before
+	return f.err
+}
+
+func TestRevokeUserTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		err            error
+		expectedStatus int
+		expectedBefore string
+	}{
+		{
+			name:           "Explicit cutoff",
+			body:           `{"before": "2026-10-01T00:00:00Z"}`,
+			expectedStatus: http.StatusOK,
+			expectedBefore: "2026-10-01T00:00:00Z",
+		},
+		{
+			name:           "Cutoff defaults to now",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Future cutoff",
+			body:           `{"before": "` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid body",
+			body:           `{"before": "yesterday"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Denylist unavailable",
+			err:            errors.New("connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoker := &fakeRevoker{err: tt.err}
+			router := gin.New()
+			router.POST("/users/:id/revoke-tokens", RevokeUserTokens(revoker))
+
+			req, _ := http.NewRequest("POST", "/users/user-42/revoke-tokens", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Empty(t, revoker.userID, "nothing is revoked")
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, "user-42", revoker.userID)
+			if tt.expectedBefore != "" {
+				assert.Equal(t, tt.expectedBefore, revoker.before.UTC().Format(time.RFC3339))
+			} else {
+				assert.WithinDuration(t, time.Now(), revoker.before, time.Minute)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// localCache is a bounded in-memory map whose entries expire
+type localCache[V any] struct {
+	max int
+
+	mu      sync.Mutex
+	entries map[string]localEntry[V]
+}
+
+type localEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+func newLocalCache[V any](max int) *localCache[V] {
+	return &localCache[V]{max: max, entries: make(map[string]localEntry[V])}
+}
+
+// get returns the value stored under key unless it has expired
+func (c *localCache[V]) get(key string) (V, bool) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if !ok || !time.Now().Before(entry.expires) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// set stores value under key until expires. When the cache is full, expired
+// entries are dropped first, then arbitrary ones.
+func (c *localCache[V]) set(key string, value V, expires time.Time) {
+	now := time.Now()
+	if !now.Before(expires) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.max {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < c.max {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = localEntry[V]{value: value, expires: expires}
+}
@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+// Denylist key prefixes: single tokens by jti or hash, and per-user cutoffs
+const (
+	revokedTokenPrefix  = "revoked_token:"
+	revokedBeforePrefix = "revoked_before:"
+)
+
+// revokeTimeout bounds recording a revocation after the response is decided
+const revokeTimeout = 2 * time.Second
+
+// raiseCutoffScript sets a user's cutoff, in unix milliseconds, unless it is
+// already later, so a revocation never undoes a newer one.
+//
+//	KEYS[1]  the cutoff
+//	ARGV[1]  the new cutoff
+//	ARGV[2]  its TTL in milliseconds
+var raiseCutoffScript = redis.NewScript(`
+local cutoff = tonumber(redis.call("GET", KEYS[1]))
+if cutoff and cutoff >= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+return 1
+`)
+
+var denylistErrors = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gateway_token_denylist_errors_total",
+		Help: "Token denylist operations that failed, by operation",
+	},
+	[]string{"operation"},
+)
+
+// Denylist records revoked tokens
+type Denylist interface {
+	// Revoked reports whether a verified token has been revoked
+	Revoked(ctx context.Context, token string, claims jwt.MapClaims) (bool, error)
+	// RevokeToken revokes a single token until it expires
+	RevokeToken(ctx context.Context, token string, claims jwt.MapClaims) error
+}
+
+// RedisDenylist keeps revoked tokens in Redis, shared by every gateway
+// replica. Single tokens are kept until they expire; a user's cutoff, which
+// revokes every token issued before it, is kept for maxLifetime and only
+// ever moves later.
+type RedisDenylist struct {
+	redis       *redis.Client
+	maxLifetime time.Duration
+	leeway      time.Duration
+}
+
+// NewRedisDenylist creates a denylist. maxLifetime is the longest a token the
+// gateway accepts can live, used for tokens without exp and for user cutoffs;
+// leeway is how long past exp tokens are still accepted.
+func NewRedisDenylist(rdb *redis.Client, maxLifetime, leeway time.Duration) *RedisDenylist {
+	return &RedisDenylist{redis: rdb, maxLifetime: maxLifetime + leeway, leeway: leeway}
+}
+
+// Revoked implements Denylist, checking the token and its user in one round trip
+func (d *RedisDenylist) Revoked(ctx context.Context, token string, claims jwt.MapClaims) (bool, error) {
+	keys := []string{tokenKey(token, claims)}
+	userID, _ := claims["sub"].(string)
+	if userID != "" {
+		keys = append(keys, revokedBeforePrefix+userID)
+	}
+
+	values, err := d.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return false, err
+	}
+	if values[0] != nil {
+		return true, nil
+	}
+	if len(values) < 2 || values[1] == nil {
+		return false, nil
+	}
+
+	cutoff, err := strconv.ParseInt(values[1].(string), 10, 64)
+	if err != nil {
+		return false, err
+	}
+	return issuedBefore(claims, cutoff), nil
+}
+
+// issuedBefore reports whether a token was issued before a cutoff in unix
+// milliseconds. iat only has whole seconds, so the cutoff is truncated to its
+// second: a token issued in that second, such as one from logging in again
+// right after the revocation, is kept.
+func issuedBefore(claims jwt.MapClaims, cutoff int64) bool {
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		// Without iat there is no telling whether the token predates the cutoff
+		return true
+	}
+	return issuedAt.Unix() < cutoff/1000
+}
+
+// RevokeToken implements Denylist
+func (d *RedisDenylist) RevokeToken(ctx context.Context, token string, claims jwt.MapClaims) error {
+	ttl := d.maxLifetime
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		ttl = time.Until(exp.Time) + d.leeway
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	return d.redis.Set(ctx, tokenKey(token, claims), 1, ttl).Err()
+}
+
+// RevokeUser revokes every token of a user issued before the second of the
+// given time. An earlier time than the user's current cutoff changes nothing.
+func (d *RedisDenylist) RevokeUser(ctx context.Context, userID string, before time.Time) error {
+	return raiseCutoffScript.Run(ctx, d.redis, []string{revokedBeforePrefix + userID},
+		before.Truncate(time.Second).UnixMilli(), d.maxLifetime.Milliseconds()).Err()
+}
+
+// tokenKey identifies a token by its jti, or by its hash when it has none
+func tokenKey(token string, claims jwt.MapClaims) string {
+	if jti, _ := claims["jti"].(string); jti != "" {
+		return revokedTokenPrefix + "jti:" + jti
+	}
+	sum := sha256.Sum256([]byte(token))
+	return revokedTokenPrefix + "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// RevokeOnSuccess denylists the request's bearer token once the rest of the
+// chain answers with a 2xx, e.g. after auth-service accepts a logout. It must
+// follow JWTAuth or RemoteJWTAuth.
+func RevokeOnSuccess(denylist Denylist) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Status() < 200 || c.Writer.Status() > 299 {
+			return
+		}
+		claims, ok := c.Get("jwt_claims")
+		if !ok {
+			return
+		}
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+
+		// The request context may already be past its deadline
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), revokeTimeout)
+		defer cancel()
+		if err := denylist.RevokeToken(ctx, token, claims.(jwt.MapClaims)); err != nil {
+			denylistErrors.WithLabelValues("revoke").Inc()
+			c.Error(err)
+		}
+	}
+}
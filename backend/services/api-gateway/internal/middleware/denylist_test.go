@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fakeDenylist keeps revoked token keys in memory
+type fakeDenylist struct {
+	revoked map[string]bool
+}
+
+func (f *fakeDenylist) Revoked(ctx context.Context, token string, claims jwt.MapClaims) (bool, error) {
+	return f.revoked[tokenKey(token, claims)], nil
+}
+
+func (f *fakeDenylist) RevokeToken(ctx context.Context, token string, claims jwt.MapClaims) error {
+	f.revoked[tokenKey(token, claims)] = true
+	return nil
+}
+
+func TestRevokeOnSuccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	denylist := &fakeDenylist{revoked: make(map[string]bool)}
+	verifier := NewJWTVerifier("test-secret", JWTOptions{Denylist: denylist})
+
+	router := gin.New()
+	router.POST("/logout", JWTAuth(verifier), RevokeOnSuccess(denylist), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	router.POST("/failed-logout", JWTAuth(verifier), RevokeOnSuccess(denylist), func(c *gin.Context) {
+		c.Status(http.StatusBadGateway)
+	})
+	router.GET("/profile", JWTAuth(verifier), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(method, path, token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	token := createValidToken(t)
+	assert.Equal(t, http.StatusOK, request("GET", "/profile", token).Code)
+
+	// A logout the upstream refused leaves the token usable
+	assert.Equal(t, http.StatusBadGateway, request("POST", "/failed-logout", token).Code)
+	assert.Equal(t, http.StatusOK, request("GET", "/profile", token).Code)
+
+	assert.Equal(t, http.StatusNoContent, request("POST", "/logout", token).Code)
+	w := request("GET", "/profile", token)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "TOKEN_REVOKED")
+}
+
+func TestDenylistUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Nothing listens on port 1
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer rdb.Close()
+	denylist := NewRedisDenylist(rdb, time.Hour, 0)
+	token := createValidToken(t)
+
+	tests := []struct {
+		name     string
+		failOpen bool
+		status   int
+	}{
+		{"Fail closed", false, http.StatusServiceUnavailable},
+		{"Fail open", true, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.WarnLevel)
+			verifier := NewJWTVerifier("test-secret", JWTOptions{
+				Denylist:         denylist,
+				DenylistFailOpen: tt.failOpen,
+				Logger:           zap.New(core),
+			})
+
+			router := gin.New()
+			router.GET("/profile", JWTAuth(verifier), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			for i := 0; i < 2; i++ {
+				req, _ := http.NewRequest("GET", "/profile", nil)
+				req.Header.Set("Authorization", "Bearer "+token)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.status, w.Code)
+				if !tt.failOpen {
+					assert.Contains(t, w.Body.String(), "REVOCATION_UNAVAILABLE")
+				}
+			}
+			assert.Equal(t, 2, logs.FilterMessage("Token denylist unavailable").Len(), "every fallback is logged")
+		})
+	}
+}
+
+func TestTokenKey(t *testing.T) {
+	assert.Equal(t, "revoked_token:jti:abc", tokenKey("token", jwt.MapClaims{"jti": "abc"}))
+	assert.Equal(t,
+		"revoked_token:sha256:3c469e9d6c5875d37a43f353d4f88e61fcf812c66eee3457465a40b0da4153e0",
+		tokenKey("token", jwt.MapClaims{}))
+}
+
+func TestIssuedBefore(t *testing.T) {
+	cutoff := time.Date(2026, 10, 16, 12, 0, 0, 700_000_000, time.UTC).UnixMilli()
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		before bool
+	}{
+		{"Earlier second", jwt.MapClaims{"iat": float64(cutoff/1000 - 1)}, true},
+		{"Same second", jwt.MapClaims{"iat": float64(cutoff / 1000)}, false},
+		{"Later second", jwt.MapClaims{"iat": float64(cutoff/1000 + 1)}, false},
+		{"No iat", jwt.MapClaims{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.before, issuedBefore(tt.claims, cutoff))
+		})
+	}
+}
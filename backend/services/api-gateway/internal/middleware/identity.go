@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Identity headers sent to upstreams. Anything under IdentityHeaderPrefix that
+// a client sends is dropped, so upstreams can trust these.
+const (
+	IdentityHeaderPrefix    = "X-User-"
+	UserIDHeader            = "X-User-Id"
+	UserEmailHeader         = "X-User-Email"
+	UserRolesHeader         = "X-User-Roles"
+	UserTokenExpiresHeader  = "X-User-Token-Expires"
+	UserTimestampHeader     = "X-User-Timestamp"
+	UserSignatureHeader     = "X-User-Signature"
+	identitySignaturePrefix = "v1="
+)
+
+// IdentityHeaders replaces client-supplied X-User-* headers with the identity
+// of the authenticated caller, if any. When signingSecret is set the headers
+// carry an HMAC-SHA256 signature so upstreams can trust them without holding
+// the JWT secret:
+//
+//	X-User-Signature: v1=hex(HMAC(secret, id \n email \n roles \n expires \n timestamp))
+//
+// where timestamp is X-User-Timestamp, in Unix seconds.
+func IdentityHeaders(signingSecret string) gin.HandlerFunc {
+	secret := []byte(signingSecret)
+
+	return func(c *gin.Context) {
+		header := c.Request.Header
+		for name := range header {
+			if strings.HasPrefix(http.CanonicalHeaderKey(name), IdentityHeaderPrefix) {
+				delete(header, name)
+			}
+		}
+
+		userID := contextString(c, "user_id")
+		if userID == "" {
+			c.Next()
+			return
+		}
+
+		email := contextString(c, "user_email")
+		roles := strings.Join(contextRoles(c), ",")
+		expires := ""
+		if claims, ok := c.Get("jwt_claims"); ok {
+			if exp, err := claims.(jwt.MapClaims).GetExpirationTime(); err == nil && exp != nil {
+				expires = strconv.FormatInt(exp.Unix(), 10)
+			}
+		}
+
+		header.Set(UserIDHeader, userID)
+		setIfNotEmpty(header, UserEmailHeader, email)
+		setIfNotEmpty(header, UserRolesHeader, roles)
+		setIfNotEmpty(header, UserTokenExpiresHeader, expires)
+
+		if len(secret) > 0 {
+			timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+			header.Set(UserTimestampHeader, timestamp)
+			header.Set(UserSignatureHeader, identitySignature(secret, userID, email, roles, expires, timestamp))
+		}
+
+		c.Next()
+	}
+}
+
+// identitySignature signs the identity header values in a fixed order
+func identitySignature(secret []byte, values ...string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strings.Join(values, "\n")))
+	return identitySignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// contextString returns a context value set from token claims as a string
+func contextString(c *gin.Context, key string) string {
+	value, ok := c.Get(key)
+	if !ok || value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+// contextRoles returns every role the caller holds
+func contextRoles(c *gin.Context) []string {
+	if roles, ok := c.Get("user_roles"); ok {
+		return roles.([]string)
+	}
+	if role := contextString(c, "user_role"); role != "" {
+		return []string{role}
+	}
+	return nil
+}
+
+func setIfNotEmpty(header http.Header, name, value string) {
+	if value != "" {
+		header.Set(name, value)
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	exp := time.Now().Add(time.Hour).Unix()
+	authenticate := func(c *gin.Context) {
+		c.Set("jwt_claims", jwt.MapClaims{"exp": float64(exp)})
+		c.Set("user_id", "user-1")
+		c.Set("user_email", "tenant@example.com")
+		c.Set("user_role", "tenant")
+		c.Next()
+	}
+
+	tests := []struct {
+		name     string
+		chain    []gin.HandlerFunc
+		secret   string
+		expected map[string]string
+	}{
+		{
+			name:     "Anonymous requests lose spoofed headers",
+			chain:    []gin.HandlerFunc{IdentityHeaders("")},
+			expected: map[string]string{UserIDHeader: "", UserRolesHeader: ""},
+		},
+		{
+			name:  "Authenticated requests carry the verified identity",
+			chain: []gin.HandlerFunc{authenticate, IdentityHeaders("")},
+			expected: map[string]string{
+				UserIDHeader:           "user-1",
+				UserEmailHeader:        "tenant@example.com",
+				UserRolesHeader:        "tenant",
+				UserTokenExpiresHeader: strconv.FormatInt(exp, 10),
+				UserSignatureHeader:    "",
+			},
+		},
+		{
+			name:     "Signed identity",
+			chain:    []gin.HandlerFunc{authenticate, IdentityHeaders("identity-signing-secret-0123456789")},
+			secret:   "identity-signing-secret-0123456789",
+			expected: map[string]string{UserIDHeader: "user-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var upstream http.Header
+			router := gin.New()
+			router.GET("/test", append(tt.chain, func(c *gin.Context) {
+				upstream = c.Request.Header.Clone()
+				c.Status(http.StatusOK)
+			})...)
+
+			req, _ := http.NewRequest("GET", "/test", nil)
+			req.Header.Set("X-User-Id", "admin-1")
+			req.Header.Set("X-User-Roles", "admin")
+			req.Header.Set("x-user-signature", "v1=forged")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			for name, value := range tt.expected {
+				assert.Equal(t, value, upstream.Get(name), name)
+			}
+			if tt.secret != "" {
+				expected := identitySignature([]byte(tt.secret),
+					"user-1", "tenant@example.com", "tenant", strconv.FormatInt(exp, 10), upstream.Get(UserTimestampHeader))
+				assert.Equal(t, expected, upstream.Get(UserSignatureHeader))
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// maxJWKSBytes bounds the JWKS document read from a URL
+const maxJWKSBytes = 1 << 20
+
+// PEMKey is a public key file and the kid tokens signed with it carry
+type PEMKey struct {
+	KID  string
+	File string
+}
+
+// KeySetOptions lists where a KeySet loads its keys from. A key that is no
+// longer listed keeps verifying for GracePeriod so tokens it signed can expire.
+type KeySetOptions struct {
+	PEM         []PEMKey
+	JWKSFile    string
+	JWKSURL     string
+	GracePeriod time.Duration
+	HTTPClient  *http.Client
+}
+
+// KeySet holds the public keys asymmetric tokens are verified against, by kid
+type KeySet struct {
+	options KeySetOptions
+
+	mu   sync.RWMutex
+	keys map[string]*setKey
+
+	stop chan struct{}
+	once sync.Once
+}
+
+type setKey struct {
+	key crypto.PublicKey
+	// removed is when the key disappeared from its source, zero while listed
+	removed time.Time
+}
+
+// NewKeySet loads the keys from every source, failing if any source cannot be
+// read so a bad key configuration is refused at startup and on reload
+func NewKeySet(ctx context.Context, options KeySetOptions) (*KeySet, error) {
+	if options.HTTPClient == nil {
+		options.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	ks := &KeySet{
+		options: options,
+		keys:    make(map[string]*setKey),
+		stop:    make(chan struct{}),
+	}
+	if err := ks.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Key returns the key with the given kid. A token without a kid may use the
+// only key of a set that has exactly one.
+func (ks *KeySet) Key(kid string) (crypto.PublicKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if kid == "" && len(ks.keys) == 1 {
+		for _, k := range ks.keys {
+			return k.key, true
+		}
+	}
+	k, ok := ks.keys[kid]
+	if !ok {
+		return nil, false
+	}
+	return k.key, true
+}
+
+// Refresh reloads every source. If any source fails the current keys are kept
+// as they are and the error is returned.
+func (ks *KeySet) Refresh(ctx context.Context) error {
+	loaded := make(map[string]crypto.PublicKey)
+
+	for _, p := range ks.options.PEM {
+		key, err := readPEMKey(p.File)
+		if err != nil {
+			return fmt.Errorf("key %q: %w", p.KID, err)
+		}
+		loaded[p.KID] = key
+	}
+
+	if ks.options.JWKSFile != "" {
+		data, err := os.ReadFile(ks.options.JWKSFile)
+		if err != nil {
+			return err
+		}
+		if err := parseJWKS(data, loaded); err != nil {
+			return fmt.Errorf("%s: %w", ks.options.JWKSFile, err)
+		}
+	}
+
+	if ks.options.JWKSURL != "" {
+		data, err := ks.fetch(ctx, ks.options.JWKSURL)
+		if err != nil {
+			return err
+		}
+		if err := parseJWKS(data, loaded); err != nil {
+			return fmt.Errorf("%s: %w", ks.options.JWKSURL, err)
+		}
+	}
+
+	now := time.Now()
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	for kid, k := range ks.keys {
+		if _, ok := loaded[kid]; ok {
+			continue
+		}
+		if k.removed.IsZero() {
+			k.removed = now
+		}
+		if now.Sub(k.removed) >= ks.options.GracePeriod {
+			delete(ks.keys, kid)
+		}
+	}
+	for kid, key := range loaded {
+		ks.keys[kid] = &setKey{key: key}
+	}
+
+	return nil
+}
+
+// Start refreshes the keys every interval until Close, passing failures to
+// onError
+func (ks *KeySet) Start(interval time.Duration, onError func(error)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ks.stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				if err := ks.Refresh(ctx); err != nil {
+					onError(err)
+				}
+				cancel()
+			}
+		}
+	}()
+}
+
+// Close stops periodic refreshing
+func (ks *KeySet) Close() {
+	ks.once.Do(func() { close(ks.stop) })
+}
+
+func (ks *KeySet) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := ks.options.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSBytes))
+}
+
+// readPEMKey reads a PEM public key or certificate
+func readPEMKey(file string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data", file)
+	}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		return cert.PublicKey, nil
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		return key, nil
+	default:
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		return key, nil
+	}
+}
+
+// jwk holds the members of a JSON Web Key used for RSA and EC keys
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS adds the signing keys of a JWKS document to keys. Keys for other
+// uses and of other types are skipped.
+func parseJWKS(data []byte, keys map[string]crypto.PublicKey) error {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return err
+	}
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		var key crypto.PublicKey
+		var err error
+		switch k.Kty {
+		case "RSA":
+			key, err = k.rsaKey()
+		case "EC":
+			key, err = k.ecKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+
+	return nil
+}
+
+func (k jwk) rsaKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid n: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid e: %w", err)
+	}
+	if len(n) == 0 || len(e) == 0 || len(e) > 4 {
+		return nil, errors.New("invalid RSA key")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+func (k jwk) ecKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid x: %w", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid y: %w", err)
+	}
+
+	key := &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+	if !curve.IsOnCurve(key.X, key.Y) {
+		return nil, errors.New("point is not on the curve")
+	}
+	return key, nil
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeJWKS writes a JWKS document with the given keys to path
+func writeJWKS(t *testing.T, path string, keys map[string]interface{}) {
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for kid, key := range keys {
+		switch k := key.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, map[string]string{
+				"kty": "RSA", "kid": kid, "use": "sig",
+				"n": encode(k.N.Bytes()), "e": encode(big.NewInt(int64(k.E)).Bytes()),
+			})
+		case *ecdsa.PublicKey:
+			set.Keys = append(set.Keys, map[string]string{
+				"kty": "EC", "kid": kid, "crv": "P-256",
+				"x": encode(k.X.Bytes()), "y": encode(k.Y.Bytes()),
+			})
+		}
+	}
+
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+// signWith signs a token valid for an hour with key, naming kid in its header
+func signWith(t *testing.T, method jwt.SigningMethod, kid string, key interface{}) string {
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
+		"sub": "test-user-id",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+// verifyCode returns the code Verify rejects a token with, or "" if accepted
+func verifyCode(verifier *JWTVerifier, token string) string {
+	if _, err := verifier.Verify(context.Background(), token); err != nil {
+		return err.(*TokenError).Code
+	}
+	return ""
+}
+
+func TestKeySet(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("JWKS file with rotation and grace period", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "jwks.json")
+		writeJWKS(t, path, map[string]interface{}{"old": &rsaKey.PublicKey, "new": &ecKey.PublicKey})
+
+		keys, err := NewKeySet(ctx, KeySetOptions{JWKSFile: path, GracePeriod: time.Hour})
+		require.NoError(t, err)
+		verifier := NewJWTVerifier("", JWTOptions{Keys: keys, Algorithms: []string{"RS256", "ES256"}})
+
+		oldToken := signWith(t, jwt.SigningMethodRS256, "old", rsaKey)
+		newToken := signWith(t, jwt.SigningMethodES256, "new", ecKey)
+		assert.Equal(t, "", verifyCode(verifier, oldToken))
+		assert.Equal(t, "", verifyCode(verifier, newToken))
+		assert.Equal(t, "TOKEN_KEY_UNKNOWN", verifyCode(verifier, signWith(t, jwt.SigningMethodRS256, "other", rsaKey)))
+		assert.Equal(t, "TOKEN_ALGORITHM_NOT_ALLOWED", verifyCode(verifier, signWith(t, jwt.SigningMethodHS256, "", []byte("secret"))))
+
+		// The retired key keeps verifying during the grace period
+		writeJWKS(t, path, map[string]interface{}{"new": &ecKey.PublicKey})
+		require.NoError(t, keys.Refresh(ctx))
+		assert.Equal(t, "", verifyCode(verifier, oldToken))
+
+		keys.options.GracePeriod = 0
+		require.NoError(t, keys.Refresh(ctx))
+		assert.Equal(t, "TOKEN_KEY_UNKNOWN", verifyCode(verifier, oldToken))
+		assert.Equal(t, "", verifyCode(verifier, newToken))
+	})
+
+	t.Run("Failed refresh keeps the current keys", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "jwks.json")
+		writeJWKS(t, path, map[string]interface{}{"current": &rsaKey.PublicKey})
+
+		keys, err := NewKeySet(ctx, KeySetOptions{JWKSFile: path})
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+		assert.Error(t, keys.Refresh(ctx))
+		_, ok := keys.Key("current")
+		assert.True(t, ok)
+	})
+
+	t.Run("JWKS URL", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "jwks.json")
+		writeJWKS(t, path, map[string]interface{}{"remote": &ecKey.PublicKey})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.ServeFile(w, r, path)
+		}))
+		defer server.Close()
+
+		keys, err := NewKeySet(ctx, KeySetOptions{JWKSURL: server.URL})
+		require.NoError(t, err)
+		verifier := NewJWTVerifier("", JWTOptions{Keys: keys, Algorithms: []string{"ES256"}})
+		assert.Equal(t, "", verifyCode(verifier, signWith(t, jwt.SigningMethodES256, "remote", ecKey)))
+	})
+
+	t.Run("PEM file", func(t *testing.T) {
+		der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "auth.pem")
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+		keys, err := NewKeySet(ctx, KeySetOptions{PEM: []PEMKey{{KID: "auth-2024", File: path}}})
+		require.NoError(t, err)
+		verifier := NewJWTVerifier("", JWTOptions{Keys: keys, Algorithms: []string{"RS256", "ES256"}})
+
+		assert.Equal(t, "", verifyCode(verifier, signWith(t, jwt.SigningMethodRS256, "auth-2024", rsaKey)))
+		// The only key of a set also verifies tokens without a kid
+		assert.Equal(t, "", verifyCode(verifier, signWith(t, jwt.SigningMethodRS256, "", rsaKey)))
+		// A key of the wrong type for the algorithm does not verify
+		assert.Equal(t, "TOKEN_SIGNATURE_INVALID", verifyCode(verifier, signWith(t, jwt.SigningMethodES256, "auth-2024", ecKey)))
+	})
+
+	t.Run("Unreadable source is refused", func(t *testing.T) {
+		_, err := NewKeySet(ctx, KeySetOptions{JWKSFile: filepath.Join(t.TempDir(), "missing.json")})
+		assert.Error(t, err)
+	})
+}
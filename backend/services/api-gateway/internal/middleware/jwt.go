@@ -1,56 +1,322 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
 )
 
-// JWTAuth validates JWT tokens
-func JWTAuth(secret string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
-			return
+// errAlgorithmNotAllowed rejects tokens signed with an algorithm the verifier
+// was not configured for, including "none" and public-key algorithms offered
+// with the HMAC secret
+var errAlgorithmNotAllowed = errors.New("signing algorithm not allowed")
+
+// errKeyUnknown rejects asymmetric tokens whose kid is not in the key set
+var errKeyUnknown = errors.New("signing key unknown")
+
+// TokenError is a rejected token, with the code reported to the client
+type TokenError struct {
+	Code    string
+	Message string
+}
+
+func (e *TokenError) Error() string {
+	return e.Message
+}
+
+// tokenErrors maps verification failures to the errors reported for them, in
+// the order they are checked
+var tokenErrors = []struct {
+	err   error
+	token *TokenError
+}{
+	{errAlgorithmNotAllowed, &TokenError{"TOKEN_ALGORITHM_NOT_ALLOWED", "Token signing algorithm not allowed"}},
+	{errKeyUnknown, &TokenError{"TOKEN_KEY_UNKNOWN", "Token signing key unknown"}},
+	{jwt.ErrTokenMalformed, &TokenError{"TOKEN_MALFORMED", "Malformed token"}},
+	{jwt.ErrTokenSignatureInvalid, &TokenError{"TOKEN_SIGNATURE_INVALID", "Invalid token signature"}},
+	{jwt.ErrTokenExpired, &TokenError{"TOKEN_EXPIRED", "Token has expired"}},
+	{jwt.ErrTokenNotValidYet, &TokenError{"TOKEN_NOT_YET_VALID", "Token is not valid yet"}},
+	{jwt.ErrTokenUsedBeforeIssued, &TokenError{"TOKEN_NOT_YET_VALID", "Token is not valid yet"}},
+	{jwt.ErrTokenInvalidIssuer, &TokenError{"TOKEN_ISSUER_INVALID", "Token issuer not accepted"}},
+	{jwt.ErrTokenInvalidAudience, &TokenError{"TOKEN_AUDIENCE_INVALID", "Token audience not accepted"}},
+	{jwt.ErrTokenRequiredClaimMissing, &TokenError{"TOKEN_CLAIM_MISSING", "Token is missing a required claim"}},
+}
+
+// errTokenInvalid covers every other verification failure
+var errTokenInvalid = &TokenError{"TOKEN_INVALID", "Invalid token"}
+
+var errTokenRevoked = &TokenError{"TOKEN_REVOKED", "Token has been revoked"}
+
+// errRevocationUnavailable refuses tokens whose revocation could not be
+// checked, with 503 rather than 401
+var errRevocationUnavailable = &TokenError{"REVOCATION_UNAVAILABLE", "Token revocation status unavailable"}
+
+// JWTOptions are the checks a token must pass besides its signature. Leeway
+// allows for clock skew on exp, nbf and iat. A MaxAge other than zero rejects
+// tokens issued longer ago than that, and requires iat. Keys verifies RS*, PS*
+// and ES* tokens, and Denylist rejects revoked ones; when it cannot be checked,
+// DenylistFailOpen accepts the token instead of refusing it. The caller's
+// roles come from the role claim and the RolesClaim array, expanded by
+// Hierarchy.
+type JWTOptions struct {
+	Keys             *KeySet
+	Denylist         Denylist
+	DenylistFailOpen bool
+	Logger           *zap.Logger
+	RolesClaim       string
+	Hierarchy        RoleHierarchy
+	Algorithms       []string
+	Issuer           string
+	Audience         string
+	RequiredClaims   []string
+	Leeway           time.Duration
+	MaxAge           time.Duration
+}
+
+// JWTVerifier checks the signature and claims of tokens signed with the HMAC
+// secret or with a key from a key set
+type JWTVerifier struct {
+	secret     []byte
+	algorithms map[string]bool
+	options    JWTOptions
+	parser     *jwt.Parser
+	validator  *jwt.Validator
+}
+
+// NewJWTVerifier creates a verifier for tokens signed with secret. Only HS256
+// is accepted unless options name other algorithms.
+func NewJWTVerifier(secret string, options JWTOptions) *JWTVerifier {
+	algorithms := make(map[string]bool)
+	for _, alg := range options.Algorithms {
+		algorithms[alg] = true
+	}
+	if len(algorithms) == 0 {
+		algorithms[jwt.SigningMethodHS256.Alg()] = true
+	}
+
+	parserOptions := []jwt.ParserOption{jwt.WithLeeway(options.Leeway), jwt.WithIssuedAt()}
+	if options.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(options.Issuer))
+	}
+	if options.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(options.Audience))
+	}
+
+	if options.Logger == nil {
+		options.Logger = zap.NewNop()
+	}
+
+	return &JWTVerifier{
+		secret:     []byte(secret),
+		algorithms: algorithms,
+		options:    options,
+		parser:     jwt.NewParser(parserOptions...),
+		validator:  jwt.NewValidator(parserOptions...),
+	}
+}
+
+// Verify checks a token and returns its claims. Rejections are *TokenError.
+func (v *JWTVerifier) Verify(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(tokenString, claims, v.key); err != nil {
+		return nil, tokenError(err)
+	}
+
+	return v.checkClaims(ctx, tokenString, claims)
+}
+
+// verifyVouched checks a token whose signature an authority has already
+// verified, such as auth-service in remote validation mode. Everything else
+// Verify checks still applies: the algorithm, the standard claims, the
+// required claims, the token's age and the denylist.
+func (v *JWTVerifier) verifyVouched(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, claims)
+	if err != nil {
+		return nil, tokenError(err)
+	}
+	if !v.algorithms[token.Method.Alg()] {
+		return nil, tokenError(errAlgorithmNotAllowed)
+	}
+	if err := v.validator.Validate(claims); err != nil {
+		return nil, tokenError(err)
+	}
+
+	return v.checkClaims(ctx, tokenString, claims)
+}
+
+// checkClaims applies the checks the jwt package does not make: required
+// claims, the token's age and the denylist
+func (v *JWTVerifier) checkClaims(ctx context.Context, tokenString string, claims jwt.MapClaims) (jwt.MapClaims, error) {
+	for _, claim := range v.options.RequiredClaims {
+		if _, ok := claims[claim]; !ok {
+			return nil, &TokenError{"TOKEN_CLAIM_MISSING", "Token is missing the " + claim + " claim"}
 		}
+	}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Bearer token required"})
-			c.Abort()
-			return
+	if v.options.MaxAge > 0 {
+		issuedAt, err := claims.GetIssuedAt()
+		if err != nil || issuedAt == nil {
+			return nil, &TokenError{"TOKEN_CLAIM_MISSING", "Token is missing the iat claim"}
+		}
+		if time.Since(issuedAt.Time) > v.options.MaxAge+v.options.Leeway {
+			return nil, &TokenError{"TOKEN_TOO_OLD", "Token is too old"}
+		}
+	}
+
+	if err := v.checkRevoked(ctx, tokenString, claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// tokenError maps a verification failure to the error reported for it
+func tokenError(err error) *TokenError {
+	for _, e := range tokenErrors {
+		if errors.Is(err, e.err) {
+			return e.token
 		}
+	}
+	return errTokenInvalid
+}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(secret), nil
-		})
+// checkRevoked consults the denylist. When it cannot answer, the token is
+// accepted or refused as DenylistFailOpen says, with a warning either way.
+func (v *JWTVerifier) checkRevoked(ctx context.Context, tokenString string, claims jwt.MapClaims) error {
+	if v.options.Denylist == nil {
+		return nil
+	}
 
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
+	revoked, err := v.options.Denylist.Revoked(ctx, tokenString, claims)
+	if err != nil {
+		denylistErrors.WithLabelValues("check").Inc()
+		v.options.Logger.Warn("Token denylist unavailable",
+			zap.Bool("fail_open", v.options.DenylistFailOpen),
+			zap.Error(err),
+		)
+		if v.options.DenylistFailOpen {
+			return nil
+		}
+		return errRevocationUnavailable
+	}
+	if revoked {
+		return errTokenRevoked
+	}
+	return nil
+}
+
+// key pins the signing algorithm before handing out the secret or the
+// token's public key. The jwt package then checks that the key's type matches
+// the algorithm.
+func (v *JWTVerifier) key(token *jwt.Token) (interface{}, error) {
+	if !v.algorithms[token.Method.Alg()] {
+		return nil, errAlgorithmNotAllowed
+	}
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if len(v.secret) == 0 {
+			return nil, errAlgorithmNotAllowed
+		}
+		return v.secret, nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		if v.options.Keys == nil {
+			return nil, errAlgorithmNotAllowed
+		}
+		kid, _ := token.Header["kid"].(string)
+		key, ok := v.options.Keys.Key(kid)
+		if !ok {
+			return nil, errKeyUnknown
+		}
+		return key, nil
+	default:
+		return nil, errAlgorithmNotAllowed
+	}
+}
+
+// JWTAuth validates JWT tokens
+func JWTAuth(verifier *JWTVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString, ok := bearerToken(c)
+		if !ok {
 			return
 		}
 
-		// Extract claims
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			c.Set("user_id", claims["sub"])
-			c.Set("user_email", claims["email"])
-			c.Set("user_role", claims["role"])
+		claims, err := verifier.Verify(c.Request.Context(), tokenString)
+		if err != nil {
+			rejectToken(c, err)
+			return
 		}
 
+		// Extract claims
+		verifier.setIdentity(c, claims, claims["sub"], claims["role"])
+
 		c.Next()
 	}
 }
 
+// setIdentity stores the caller's claims and identity in the context. Besides
+// the single user_role, user_roles lists every role the caller holds,
+// including those implied by the hierarchy.
+func (v *JWTVerifier) setIdentity(c *gin.Context, claims jwt.MapClaims, userID, role interface{}) {
+	roles := claimRoles(claims[v.options.RolesClaim])
+	if r, ok := role.(string); ok && r != "" {
+		roles = append([]string{r}, roles...)
+	}
+
+	c.Set("jwt_claims", claims)
+	c.Set("user_id", userID)
+	c.Set("user_email", claims["email"])
+	c.Set("user_role", role)
+	c.Set("user_roles", v.options.Hierarchy.Expand(roles))
+}
+
+// bearerToken returns the request's bearer token, rejecting the request if
+// there is none
+func bearerToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required", "code": "AUTHORIZATION_REQUIRED"})
+		c.Abort()
+		return "", false
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Bearer token required", "code": "BEARER_TOKEN_REQUIRED"})
+		c.Abort()
+		return "", false
+	}
+
+	return tokenString, true
+}
+
+// rejectToken responds 401 with the error's code, or 503 when the token could
+// not be checked
+func rejectToken(c *gin.Context, err error) {
+	var tokenErr *TokenError
+	if !errors.As(err, &tokenErr) {
+		tokenErr = errTokenInvalid
+	}
+	status := http.StatusUnauthorized
+	if tokenErr == errRevocationUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, gin.H{"error": tokenErr.Message, "code": tokenErr.Code})
+	c.Abort()
+}
+
 // AdminOnly restricts access to admin users only
 func AdminOnly() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("user_role")
-		if !exists || role != "admin" {
+		if !hasAnyRole(c, []string{"admin"}) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
 			c.Abort()
 			return
@@ -58,3 +324,8 @@ func AdminOnly() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireRole restricts access to users with the given role
+func RequireRole(required string) gin.HandlerFunc {
+	return RequireAnyRole(required)
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -36,7 +37,7 @@ func TestJWTAuth(t *testing.T) {
 			name:           "Invalid JWT token",
 			authHeader:     "Bearer invalid-token",
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   "Invalid token",
+			expectedBody:   "TOKEN_MALFORMED",
 		},
 		{
 			name:           "Valid JWT token",
@@ -49,7 +50,7 @@ func TestJWTAuth(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			router := gin.New()
-			router.Use(JWTAuth("test-secret"))
+			router.Use(JWTAuth(NewJWTVerifier("test-secret", JWTOptions{})))
 			router.GET("/test", func(c *gin.Context) {
 				c.JSON(http.StatusOK, gin.H{"message": "success"})
 			})
@@ -69,6 +70,125 @@ func TestJWTAuth(t *testing.T) {
 	}
 }
 
+func TestJWTVerifier(t *testing.T) {
+	now := time.Now()
+	claims := func(modify func(jwt.MapClaims)) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub": "test-user-id",
+			"iss": "auth-service",
+			"aud": "api-gateway",
+			"iat": now.Add(-time.Minute).Unix(),
+			"exp": now.Add(time.Hour).Unix(),
+		}
+		modify(c)
+		return c
+	}
+	options := JWTOptions{
+		Algorithms:     []string{"HS256"},
+		Issuer:         "auth-service",
+		Audience:       "api-gateway",
+		RequiredClaims: []string{"sub", "exp"},
+		Leeway:         30 * time.Second,
+		MaxAge:         24 * time.Hour,
+	}
+
+	tests := []struct {
+		name   string
+		method jwt.SigningMethod
+		claims jwt.MapClaims
+		code   string
+	}{
+		{
+			name:   "Valid token",
+			method: jwt.SigningMethodHS256,
+			claims: claims(func(jwt.MapClaims) {}),
+		},
+		{
+			name:   "Algorithm not pinned",
+			method: jwt.SigningMethodHS512,
+			claims: claims(func(jwt.MapClaims) {}),
+			code:   "TOKEN_ALGORITHM_NOT_ALLOWED",
+		},
+		{
+			name:   "Unsigned token",
+			method: jwt.SigningMethodNone,
+			claims: claims(func(jwt.MapClaims) {}),
+			code:   "TOKEN_ALGORITHM_NOT_ALLOWED",
+		},
+		{
+			name:   "Expired",
+			method: jwt.SigningMethodHS256,
+			claims: claims(func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Minute).Unix() }),
+			code:   "TOKEN_EXPIRED",
+		},
+		{
+			name:   "Expired within leeway",
+			method: jwt.SigningMethodHS256,
+			claims: claims(func(c jwt.MapClaims) { c["exp"] = now.Add(-10 * time.Second).Unix() }),
+		},
+		{
+			name:   "Not valid yet",
+			method: jwt.SigningMethodHS256,
+			claims: claims(func(c jwt.MapClaims) { c["nbf"] = now.Add(time.Hour).Unix() }),
+			code:   "TOKEN_NOT_YET_VALID",
+		},
+		{
+			name:   "Wrong issuer",
+			method: jwt.SigningMethodHS256,
+			claims: claims(func(c jwt.MapClaims) { c["iss"] = "someone-else" }),
+			code:   "TOKEN_ISSUER_INVALID",
+		},
+		{
+			name:   "Wrong audience",
+			method: jwt.SigningMethodHS256,
+			claims: claims(func(c jwt.MapClaims) { c["aud"] = "billing" }),
+			code:   "TOKEN_AUDIENCE_INVALID",
+		},
+		{
+			name:   "Missing exp",
+			method: jwt.SigningMethodHS256,
+			claims: claims(func(c jwt.MapClaims) { delete(c, "exp") }),
+			code:   "TOKEN_CLAIM_MISSING",
+		},
+		{
+			name:   "Older than max age",
+			method: jwt.SigningMethodHS256,
+			claims: claims(func(c jwt.MapClaims) { c["iat"] = now.Add(-48 * time.Hour).Unix() }),
+			code:   "TOKEN_TOO_OLD",
+		},
+	}
+
+	verifier := NewJWTVerifier("test-secret", options)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var key interface{} = []byte("test-secret")
+			if tt.method == jwt.SigningMethodNone {
+				key = jwt.UnsafeAllowNoneSignatureType
+			}
+			token, err := jwt.NewWithClaims(tt.method, tt.claims).SignedString(key)
+			if err != nil {
+				t.Fatalf("Failed to create token: %v", err)
+			}
+
+			// Tokens vouched for by auth-service get the same checks but the signature
+			_, err = verifier.Verify(context.Background(), token)
+			_, vouchedErr := verifier.verifyVouched(context.Background(), token)
+			if tt.code == "" {
+				assert.NoError(t, err)
+				assert.NoError(t, vouchedErr)
+				return
+			}
+			var tokenErr *TokenError
+			if assert.ErrorAs(t, err, &tokenErr) {
+				assert.Equal(t, tt.code, tokenErr.Code)
+			}
+			if assert.ErrorAs(t, vouchedErr, &tokenErr) {
+				assert.Equal(t, tt.code, tokenErr.Code)
+			}
+		})
+	}
+}
+
 func TestAdminOnly(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// maxLocalBuckets bounds the in-memory rate limit state
+const maxLocalBuckets = 100000
+
+// limiterRetryInterval is how long a failing limiter is left alone before it
+// is tried again, so an outage does not cost every request a timeout
+const limiterRetryInterval = time.Second
+
+// errLimiterUnavailable refuses requests while the limiter fails closed
+var errLimiterUnavailable = errors.New("rate limiter unavailable")
+
+var (
+	rateLimiterDegraded = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "gateway_rate_limiter_degraded",
+			Help: "Whether rate limiting has fallen back from Redis (1) or not (0)",
+		},
+	)
+
+	rateLimiterModeSwitches = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gateway_rate_limiter_mode_switches_total",
+			Help: "Rate limiter switches by the mode switched to: redis, open, local or closed",
+		},
+		[]string{"mode"},
+	)
+)
+
+// LocalLimiter keeps rate limits in process memory, for when Redis is
+// unavailable. It runs the same algorithm as RedisLimiter, but each replica
+// enforces only its share of a limit, assuming traffic is spread evenly.
+type LocalLimiter struct {
+	replicas int
+	now      func() time.Time
+
+	mu   sync.Mutex
+	tats *localCache[time.Time]
+}
+
+// NewLocalLimiter creates a limiter for one of replicas gateway replicas
+func NewLocalLimiter(replicas int) *LocalLimiter {
+	if replicas < 1 {
+		replicas = 1
+	}
+	return &LocalLimiter{
+		replicas: replicas,
+		now:      time.Now,
+		tats:     newLocalCache[time.Time](maxLocalBuckets),
+	}
+}
+
+// Allow implements Limiter
+func (l *LocalLimiter) Allow(ctx context.Context, key string, limit Limit, cost int) (*LimitResult, error) {
+	limit = limit.share(l.replicas)
+	burst := limit.burst()
+	if burst < cost {
+		// A share smaller than the request would refuse it forever
+		burst = cost
+	}
+	interval := time.Duration(limit.interval()) * time.Microsecond
+	capacity := time.Duration(burst) * interval
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	tat, ok := l.tats.get(key)
+	if !ok || tat.Before(now) {
+		tat = now
+	}
+
+	allowAt := tat.Add(time.Duration(cost)*interval - capacity)
+	if now.Before(allowAt) {
+		return &LimitResult{
+			Limit:      burst,
+			Remaining:  int((capacity - tat.Sub(now)) / interval),
+			RetryAfter: allowAt.Sub(now),
+			ResetAfter: tat.Sub(now),
+		}, nil
+	}
+
+	tat = tat.Add(time.Duration(cost) * interval)
+	l.tats.set(key, tat, tat)
+	return &LimitResult{
+		Allowed:    true,
+		Limit:      burst,
+		Remaining:  int((capacity - tat.Sub(now)) / interval),
+		ResetAfter: tat.Sub(now),
+	}, nil
+}
+
+// share is one replica's part of the limit, rounded up
+func (l Limit) share(replicas int) Limit {
+	if replicas <= 1 {
+		return l
+	}
+	return Limit{
+		Requests: (l.Requests + replicas - 1) / replicas,
+		Period:   l.Period,
+		Burst:    (l.burst() + replicas - 1) / replicas,
+	}
+}
+
+// allow asks the limiter, falling back according to the failure mode while it
+// fails. A nil result without an error lets the request through; an error
+// refuses it.
+func (r *RateLimiter) allow(ctx context.Context, key string, limit Limit, cost int) (*LimitResult, error) {
+	if !r.degraded.Load() || time.Now().UnixNano() >= r.retryAt.Load() {
+		result, err := r.limiter.Allow(ctx, key, limit, cost)
+		if err == nil {
+			r.restore()
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			// The client went away; Redis is not to blame
+			return nil, nil
+		}
+		r.degrade(err)
+	}
+
+	switch r.failureMode {
+	case RateLimitFailLocal:
+		return r.local.Allow(ctx, key, limit, cost)
+	case RateLimitFailClosed:
+		return nil, errLimiterUnavailable
+	default:
+		return nil, nil
+	}
+}
+
+// degrade switches to the failure mode, logging the switch once
+func (r *RateLimiter) degrade(err error) {
+	r.retryAt.Store(time.Now().Add(limiterRetryInterval).UnixNano())
+	if !r.degraded.CompareAndSwap(false, true) {
+		return
+	}
+
+	rateLimiterDegraded.Set(1)
+	rateLimiterModeSwitches.WithLabelValues(r.failureMode).Inc()
+	r.logger.Warn("Rate limiter unavailable, switching to fallback",
+		zap.String("mode", r.failureMode),
+		zap.Error(err),
+	)
+}
+
+// restore switches back once the limiter answers again
+func (r *RateLimiter) restore() {
+	if !r.degraded.CompareAndSwap(true, false) {
+		return
+	}
+
+	rateLimiterDegraded.Set(0)
+	rateLimiterModeSwitches.WithLabelValues("redis").Inc()
+	r.logger.Info("Rate limiter recovered, switching back to Redis")
+}
@@ -0,0 +1,539 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Login subject kinds: failed logins count against the account identifier
+// and the client IP
+const (
+	LoginSubjectIdentifier = "identifier"
+	LoginSubjectIP         = "ip"
+)
+
+// Login state key prefixes: failure counts and lockouts, by subject
+const (
+	loginFailuresPrefix = "login_failures:"
+	loginLockoutPrefix  = "login_lockout:"
+)
+
+// maxLoginBody bounds how much of a login body is read for the identifier
+const maxLoginBody = 64 << 10
+
+// maxIdentifierLength bounds identifiers kept in Redis; longer ones are cut
+const maxIdentifierLength = 320
+
+// loginTimeout bounds recording a login's outcome after the response is decided
+const loginTimeout = 2 * time.Second
+
+var (
+	loginFailures = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "gateway_login_failures_total",
+			Help: "Failed logins on protected login routes",
+		},
+	)
+
+	loginRejections = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gateway_login_rejections_total",
+			Help: "Login attempts refused by the gateway, by reason: delayed or locked",
+		},
+		[]string{"reason"},
+	)
+
+	loginLockouts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gateway_login_lockouts_total",
+			Help: "Login lockouts started, by subject kind: identifier or ip",
+		},
+		[]string{"kind"},
+	)
+
+	loginProtectionErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gateway_login_protection_errors_total",
+			Help: "Login protection operations that failed, by operation",
+		},
+		[]string{"operation"},
+	)
+)
+
+// LoginSubject is what failed logins are counted against
+type LoginSubject struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// NewLoginSubject creates a subject, folding identifiers to lower case so
+// "Alice@Example.com" and "alice@example.com" share their failures
+func NewLoginSubject(kind, value string) LoginSubject {
+	value = strings.TrimSpace(value)
+	if kind == LoginSubjectIdentifier {
+		value = strings.ToLower(value)
+		if len(value) > maxIdentifierLength {
+			value = value[:maxIdentifierLength]
+		}
+	}
+	return LoginSubject{Kind: kind, Value: value}
+}
+
+func (s LoginSubject) String() string {
+	return s.Kind + ":" + s.Value
+}
+
+// LoginState is a subject's recent failures and lockout. Attempts still
+// waiting for an answer count as failures.
+type LoginState struct {
+	Failures    int
+	LastFailure time.Time
+	LockedUntil time.Time
+	// LockStarted is set when the attempt that read the state locked the
+	// subject out
+	LockStarted bool
+}
+
+// LoginLockout is a subject locked out of logging in
+type LoginLockout struct {
+	LoginSubject
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// LoginAttempts records failed logins and lockouts
+type LoginAttempts interface {
+	// Attempt reserves an attempt at a time for every subject at once, as a
+	// failure under its policy, unless one of them is locked out or must wait.
+	// A subject already at its lockout threshold is locked out on the spot. It
+	// reports whether the attempt may go ahead, with each subject's state.
+	Attempt(ctx context.Context, subjects []LoginSubject, policies []LoginPolicy, at time.Time) (bool, []LoginState, error)
+	// Refund takes back a reserved attempt that did not fail
+	Refund(ctx context.Context, subject LoginSubject) error
+	// Reset clears a subject's failures
+	Reset(ctx context.Context, subject LoginSubject) error
+	// Lockouts lists the subjects locked out
+	Lockouts(ctx context.Context) ([]LoginLockout, error)
+	// Unlock lifts a subject's lockout and clears its failures, reporting
+	// whether it was locked out
+	Unlock(ctx context.Context, subject LoginSubject) (bool, error)
+}
+
+// loginAttemptScript checks every subject of a login attempt and, when none
+// is locked out or delayed, counts the attempt against all of them. A
+// subject at its lockout threshold is locked out instead. See LoginPolicy.wait
+// for the delay.
+//
+//	KEYS     each subject's failures hash and lockout key, in pairs
+//	ARGV[1]  the time of the attempt, in unix milliseconds
+//	ARGV[2+] for each subject: window, delay_after, delay, max_delay,
+//	         lockout_after and lockout, durations in milliseconds
+//
+// It returns {allowed, {{failures, last failure, locked until, lock started}, ...}}.
+var loginAttemptScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local allowed = 1
+local states = {}
+
+for i = 1, #KEYS / 2 do
+	local failures, lockout = KEYS[2 * i - 1], KEYS[2 * i]
+	local arg = 2 + 6 * (i - 1)
+	local delay_after = tonumber(ARGV[arg + 1])
+	local delay = tonumber(ARGV[arg + 2])
+	local max_delay = tonumber(ARGV[arg + 3])
+	local lockout_after = tonumber(ARGV[arg + 4])
+	local lockout_ms = tonumber(ARGV[arg + 5])
+
+	local locked_until = tonumber(redis.call("GET", lockout)) or 0
+	local state = redis.call("HMGET", failures, "count", "last")
+	local count = tonumber(state[1]) or 0
+	local last = tonumber(state[2]) or 0
+	local started = 0
+
+	if locked_until <= now and lockout_after > 0 and count >= lockout_after then
+		locked_until = now + lockout_ms
+		redis.call("SET", lockout, locked_until, "PX", lockout_ms)
+		redis.call("DEL", failures)
+		count, last, started = 0, 0, 1
+	end
+
+	if locked_until > now then
+		allowed = 0
+	elseif delay_after > 0 and count >= delay_after then
+		local wait = delay
+		for _ = delay_after + 1, count do
+			if wait >= max_delay then
+				break
+			end
+			wait = wait * 2
+		end
+		if last + math.min(wait, max_delay) > now then
+			allowed = 0
+		end
+	end
+
+	states[i] = {count, last, locked_until, started}
+end
+
+if allowed == 1 then
+	for i = 1, #KEYS / 2 do
+		local failures = KEYS[2 * i - 1]
+		states[i][1] = redis.call("HINCRBY", failures, "count", 1)
+		states[i][2] = now
+		redis.call("HSET", failures, "last", now)
+		redis.call("PEXPIRE", failures, ARGV[2 + 6 * (i - 1)])
+	end
+end
+
+return {allowed, states}
+`)
+
+// refundScript takes back one failure, unless they were cleared meanwhile
+var refundScript = redis.NewScript(`
+local count = tonumber(redis.call("HGET", KEYS[1], "count"))
+if count and count > 0 then
+	redis.call("HINCRBY", KEYS[1], "count", -1)
+end
+return 0
+`)
+
+// RedisLoginAttempts keeps failed logins in Redis, shared by every gateway
+// replica. Failures are a hash of count and last failure time; a lockout is
+// its end time, expiring with it.
+type RedisLoginAttempts struct {
+	redis *redis.Client
+}
+
+// NewRedisLoginAttempts creates login attempt records backed by rdb
+func NewRedisLoginAttempts(rdb *redis.Client) *RedisLoginAttempts {
+	return &RedisLoginAttempts{redis: rdb}
+}
+
+// Attempt implements LoginAttempts in one atomic script
+func (a *RedisLoginAttempts) Attempt(ctx context.Context, subjects []LoginSubject, policies []LoginPolicy, at time.Time) (bool, []LoginState, error) {
+	keys := make([]string, 0, 2*len(subjects))
+	args := []interface{}{at.UnixMilli()}
+	for i, subject := range subjects {
+		p := policies[i]
+		keys = append(keys, loginFailuresPrefix+subject.String(), loginLockoutPrefix+subject.String())
+		args = append(args, p.Window.Milliseconds(), p.DelayAfter, p.Delay.Milliseconds(),
+			p.MaxDelay.Milliseconds(), p.LockoutAfter, p.Lockout.Milliseconds())
+	}
+
+	values, err := loginAttemptScript.Run(ctx, a.redis, keys, args...).Slice()
+	if err != nil {
+		return false, nil, err
+	}
+
+	rows, _ := values[1].([]interface{})
+	states := make([]LoginState, len(subjects))
+	for i := range states {
+		row, _ := rows[i].([]interface{})
+		if len(row) < 4 {
+			return false, nil, fmt.Errorf("unexpected login attempt result %v", values)
+		}
+		states[i] = LoginState{
+			Failures:    int(row[0].(int64)),
+			LastFailure: unixMilli(row[1].(int64)),
+			LockedUntil: unixMilli(row[2].(int64)),
+			LockStarted: row[3].(int64) == 1,
+		}
+	}
+	return values[0].(int64) == 1, states, nil
+}
+
+// Refund implements LoginAttempts
+func (a *RedisLoginAttempts) Refund(ctx context.Context, subject LoginSubject) error {
+	return refundScript.Run(ctx, a.redis, []string{loginFailuresPrefix + subject.String()}).Err()
+}
+
+// Reset implements LoginAttempts
+func (a *RedisLoginAttempts) Reset(ctx context.Context, subject LoginSubject) error {
+	return a.redis.Del(ctx, loginFailuresPrefix+subject.String()).Err()
+}
+
+// Lockouts implements LoginAttempts, scanning for lockout keys
+func (a *RedisLoginAttempts) Lockouts(ctx context.Context) ([]LoginLockout, error) {
+	var lockouts []LoginLockout
+	iter := a.redis.Scan(ctx, 0, loginLockoutPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		until, err := a.redis.Get(ctx, key).Result()
+		if err == redis.Nil {
+			// Expired since the scan
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		kind, value, _ := strings.Cut(strings.TrimPrefix(key, loginLockoutPrefix), ":")
+		lockouts = append(lockouts, LoginLockout{
+			LoginSubject: LoginSubject{Kind: kind, Value: value},
+			LockedUntil:  parseUnixMilli(until),
+		})
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(lockouts, func(i, j int) bool {
+		return lockouts[i].LockedUntil.Before(lockouts[j].LockedUntil)
+	})
+	return lockouts, nil
+}
+
+// Unlock implements LoginAttempts
+func (a *RedisLoginAttempts) Unlock(ctx context.Context, subject LoginSubject) (bool, error) {
+	var unlocked *redis.IntCmd
+	_, err := a.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		unlocked = pipe.Del(ctx, loginLockoutPrefix+subject.String())
+		pipe.Del(ctx, loginFailuresPrefix+subject.String())
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return unlocked.Val() > 0, nil
+}
+
+func parseUnixMilli(value string) time.Time {
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// unixMilli converts unix milliseconds, leaving zero as the zero time
+func unixMilli(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// LoginPolicy delays and then locks out logins after repeated failures. From
+// DelayAfter failures on, the next attempt must wait Delay after the last
+// failure, doubling with every further failure up to MaxDelay. After
+// LockoutAfter failures, the next attempt locks logins out for Lockout.
+// Failures are forgotten after Window without one.
+type LoginPolicy struct {
+	Window       time.Duration
+	DelayAfter   int
+	Delay        time.Duration
+	MaxDelay     time.Duration
+	LockoutAfter int
+	Lockout      time.Duration
+}
+
+// wait is how long after the last of failures the next attempt must wait
+func (p LoginPolicy) wait(failures int) time.Duration {
+	if p.DelayAfter <= 0 || failures < p.DelayAfter {
+		return 0
+	}
+	delay := p.Delay
+	for i := p.DelayAfter; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+// LoginGuardOptions configure a LoginGuard. A login counts as failed when the
+// upstream answers one of FailureStatuses; the identifier is the first of
+// IdentifierFields found in the JSON body.
+type LoginGuardOptions struct {
+	IdentifierFields []string
+	FailureStatuses  []int
+	Identifier       LoginPolicy
+	IP               LoginPolicy
+	Logger           *zap.Logger
+}
+
+// LoginGuard counts failed logins per account identifier and per client IP,
+// and holds back further attempts with growing delays and then a lockout
+type LoginGuard struct {
+	attempts LoginAttempts
+	opts     LoginGuardOptions
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+// NewLoginGuard creates a guard recording attempts in attempts
+func NewLoginGuard(attempts LoginAttempts, opts LoginGuardOptions) *LoginGuard {
+	g := &LoginGuard{attempts: attempts, opts: opts, logger: opts.Logger, now: time.Now}
+	if g.logger == nil {
+		g.logger = zap.NewNop()
+	}
+	return g
+}
+
+// Protect refuses attempts while the caller's identifier or IP is delayed or
+// locked out, with 429 LOGIN_DELAYED or LOGIN_LOCKED. Attempts it lets
+// through are counted as failures before they reach the upstream, so parallel
+// guesses cannot slip past the limits, and taken back unless they fail.
+// Refused attempts are not counted. When the records cannot be reached,
+// logins go ahead unguarded rather than not at all; rate limits still apply.
+func (g *LoginGuard) Protect() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subjects := []LoginSubject{NewLoginSubject(LoginSubjectIP, c.ClientIP())}
+		if id := loginIdentifier(peekBody(c.Request, maxLoginBody), g.opts.IdentifierFields); id != "" {
+			subjects = append(subjects, NewLoginSubject(LoginSubjectIdentifier, id))
+		}
+		policies := make([]LoginPolicy, len(subjects))
+		for i, subject := range subjects {
+			policies[i] = g.policy(subject)
+		}
+
+		now := g.now()
+		allowed, states, err := g.attempts.Attempt(c.Request.Context(), subjects, policies, now)
+		if err != nil {
+			loginProtectionErrors.WithLabelValues("check").Inc()
+			c.Error(err)
+			c.Next()
+			return
+		}
+		for i, state := range states {
+			if state.LockStarted {
+				loginLockouts.WithLabelValues(subjects[i].Kind).Inc()
+				g.logger.Warn("Login locked out after repeated failures",
+					zap.String("kind", subjects[i].Kind),
+					zap.String("value", subjects[i].Value),
+					zap.Int("failures", policies[i].LockoutAfter),
+					zap.Time("until", state.LockedUntil),
+				)
+			}
+		}
+		if !allowed {
+			g.reject(c, states, policies, now)
+			return
+		}
+
+		c.Next()
+
+		// The request context may already be past its deadline
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), loginTimeout)
+		defer cancel()
+
+		status := c.Writer.Status()
+		if slices.Contains(g.opts.FailureStatuses, status) {
+			loginFailures.Inc()
+			return
+		}
+		success := status >= 200 && status <= 299
+		for _, subject := range subjects {
+			// A success clears the account but not the IP; an IP stuffing
+			// credentials may well hit a valid one now and then
+			if success && subject.Kind == LoginSubjectIdentifier {
+				if err := g.attempts.Reset(ctx, subject); err != nil {
+					loginProtectionErrors.WithLabelValues("reset").Inc()
+					c.Error(err)
+				}
+				continue
+			}
+			if err := g.attempts.Refund(ctx, subject); err != nil {
+				loginProtectionErrors.WithLabelValues("refund").Inc()
+				c.Error(err)
+			}
+		}
+	}
+}
+
+// reject refuses an attempt for the subject locked out the longest, or else
+// the one that must wait the longest
+func (g *LoginGuard) reject(c *gin.Context, states []LoginState, policies []LoginPolicy, now time.Time) {
+	var lockedUntil, retryAt time.Time
+	for i, state := range states {
+		if state.LockedUntil.After(lockedUntil) {
+			lockedUntil = state.LockedUntil
+		}
+		next := state.LastFailure.Add(policies[i].wait(state.Failures))
+		if next.After(retryAt) {
+			retryAt = next
+		}
+	}
+
+	if lockedUntil.After(now) {
+		rejectLogin(c, "locked", lockedUntil.Sub(now), gin.H{
+			"error":        "Too many failed logins, try again later",
+			"code":         "LOGIN_LOCKED",
+			"locked_until": lockedUntil.UTC().Format(time.RFC3339),
+		})
+		return
+	}
+	rejectLogin(c, "delayed", retryAt.Sub(now), gin.H{
+		"error": "Too many failed logins, wait before trying again",
+		"code":  "LOGIN_DELAYED",
+	})
+}
+
+func (g *LoginGuard) policy(subject LoginSubject) LoginPolicy {
+	if subject.Kind == LoginSubjectIdentifier {
+		return g.opts.Identifier
+	}
+	return g.opts.IP
+}
+
+// rejectLogin refuses an attempt until it may be retried
+func rejectLogin(c *gin.Context, reason string, wait time.Duration, body gin.H) {
+	retryAfter := max(ceilSeconds(wait), 1)
+	loginRejections.WithLabelValues(reason).Inc()
+
+	body["retry_after"] = retryAfter
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, body)
+	c.Abort()
+}
+
+// peekBody reads up to maxBytes of the request body, leaving the whole body
+// to be read again downstream
+func peekBody(req *http.Request, maxBytes int64) []byte {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	buf, _ := io.ReadAll(io.LimitReader(req.Body, maxBytes))
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), req.Body), req.Body}
+	return buf
+}
+
+// loginIdentifier returns the first of fields set in a JSON object body
+func loginIdentifier(body []byte, fields []string) string {
+	var values map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return ""
+	}
+
+	for _, field := range fields {
+		var id string
+		switch value := values[field].(type) {
+		case string:
+			id = value
+		case json.Number:
+			id = value.String()
+		}
+		if id = strings.TrimSpace(id); id != "" {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,303 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLoginAttempts keeps login records in memory
+type fakeLoginAttempts struct {
+	mu     sync.Mutex
+	states map[LoginSubject]LoginState
+	err    error
+}
+
+func newFakeLoginAttempts() *fakeLoginAttempts {
+	return &fakeLoginAttempts{states: make(map[LoginSubject]LoginState)}
+}
+
+func (f *fakeLoginAttempts) Attempt(ctx context.Context, subjects []LoginSubject, policies []LoginPolicy, at time.Time) (bool, []LoginState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return false, nil, f.err
+	}
+
+	allowed := true
+	states := make([]LoginState, len(subjects))
+	for i, subject := range subjects {
+		state, policy := f.states[subject], policies[i]
+		if !state.LockedUntil.After(at) && policy.LockoutAfter > 0 && state.Failures >= policy.LockoutAfter {
+			state = LoginState{LockedUntil: at.Add(policy.Lockout), LockStarted: true}
+			f.states[subject] = LoginState{LockedUntil: state.LockedUntil}
+		}
+		if state.LockedUntil.After(at) || state.LastFailure.Add(policy.wait(state.Failures)).After(at) {
+			allowed = false
+		}
+		states[i] = state
+	}
+	if allowed {
+		for i, subject := range subjects {
+			states[i].Failures++
+			states[i].LastFailure = at
+			f.states[subject] = LoginState{Failures: states[i].Failures, LastFailure: at, LockedUntil: states[i].LockedUntil}
+		}
+	}
+	return allowed, states, nil
+}
+
+func (f *fakeLoginAttempts) Refund(ctx context.Context, subject LoginSubject) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	state := f.states[subject]
+	if state.Failures > 0 {
+		state.Failures--
+	}
+	f.states[subject] = state
+	return nil
+}
+
+func (f *fakeLoginAttempts) Reset(ctx context.Context, subject LoginSubject) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	state := f.states[subject]
+	state.Failures, state.LastFailure = 0, time.Time{}
+	f.states[subject] = state
+	return nil
+}
+
+func (f *fakeLoginAttempts) Lockouts(ctx context.Context) ([]LoginLockout, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var lockouts []LoginLockout
+	for subject, state := range f.states {
+		if !state.LockedUntil.IsZero() {
+			lockouts = append(lockouts, LoginLockout{LoginSubject: subject, LockedUntil: state.LockedUntil})
+		}
+	}
+	return lockouts, nil
+}
+
+func (f *fakeLoginAttempts) Unlock(ctx context.Context, subject LoginSubject) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.states[subject]
+	delete(f.states, subject)
+	return ok, nil
+}
+
+func TestLoginPolicyWait(t *testing.T) {
+	policy := LoginPolicy{DelayAfter: 3, Delay: time.Second, MaxDelay: 5 * time.Second}
+
+	tests := []struct {
+		failures int
+		wait     time.Duration
+	}{
+		{0, 0},
+		{2, 0},
+		{3, time.Second},
+		{4, 2 * time.Second},
+		{5, 4 * time.Second},
+		{6, 5 * time.Second},
+		{60, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.wait, policy.wait(tt.failures), "%d failures", tt.failures)
+	}
+
+	assert.Zero(t, LoginPolicy{}.wait(100))
+}
+
+func TestLoginIdentifier(t *testing.T) {
+	fields := []string{"identifier", "email", "phone"}
+
+	assert.Equal(t, "Alice@Example.com", loginIdentifier([]byte(`{"identifier": " Alice@Example.com ", "password": "x"}`), fields))
+	assert.Equal(t, "8801712345678", loginIdentifier([]byte(`{"email": "", "phone": 8801712345678}`), fields))
+	assert.Empty(t, loginIdentifier([]byte(`{"username": "alice"}`), fields))
+	assert.Empty(t, loginIdentifier([]byte(`identifier=alice`), fields))
+	assert.Equal(t, "identifier:alice@example.com", NewLoginSubject(LoginSubjectIdentifier, " Alice@Example.com").String())
+}
+
+func TestLoginGuard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	attempts := newFakeLoginAttempts()
+	guard := NewLoginGuard(attempts, LoginGuardOptions{
+		IdentifierFields: []string{"identifier"},
+		FailureStatuses:  []int{http.StatusUnauthorized},
+		Identifier:       LoginPolicy{Window: time.Hour, DelayAfter: 2, Delay: 10 * time.Second, MaxDelay: time.Minute, LockoutAfter: 4, Lockout: 15 * time.Minute},
+		IP:               LoginPolicy{Window: time.Hour, LockoutAfter: 6, Lockout: time.Hour},
+	})
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	guard.now = func() time.Time { return now }
+
+	router := gin.New()
+	router.POST("/login", guard.Protect(), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		if strings.Contains(string(body), `"password":"right"`) {
+			c.Status(http.StatusOK)
+			return
+		}
+		c.Status(http.StatusUnauthorized)
+	})
+
+	login := func(identifier, password string) *httptest.ResponseRecorder {
+		body := `{"identifier":"` + identifier + `","password":"` + password + `"}`
+		req, _ := http.NewRequest("POST", "/login", strings.NewReader(body))
+		req.RemoteAddr = "203.0.113.7:4000"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	alice := NewLoginSubject(LoginSubjectIdentifier, "alice@example.com")
+
+	// The body reaches the upstream intact
+	assert.Equal(t, http.StatusUnauthorized, login("alice@example.com", "wrong").Code)
+	assert.Equal(t, http.StatusUnauthorized, login("Alice@Example.com", "wrong").Code)
+	assert.Equal(t, 2, attempts.states[alice].Failures)
+
+	w := login("alice@example.com", "right")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "10", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "LOGIN_DELAYED")
+	assert.Equal(t, 2, attempts.states[alice].Failures, "refused attempts are not counted")
+
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, http.StatusUnauthorized, login("alice@example.com", "wrong").Code)
+	now = now.Add(20 * time.Second)
+	assert.Equal(t, http.StatusUnauthorized, login("alice@example.com", "wrong").Code)
+
+	w = login("alice@example.com", "right")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "900", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "LOGIN_LOCKED")
+	assert.Contains(t, w.Body.String(), `"locked_until":"2026-10-16T12:15:30Z"`)
+
+	// Other accounts are still open from the same IP, until it is locked too
+	assert.Equal(t, http.StatusOK, login("bob@example.com", "right").Code)
+	assert.Equal(t, http.StatusUnauthorized, login("carol@example.com", "wrong").Code)
+	assert.Equal(t, http.StatusUnauthorized, login("dave@example.com", "wrong").Code)
+	w = login("bob@example.com", "right")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "3600", w.Header().Get("Retry-After"))
+
+	// Success clears the account's failures but not the IP's
+	attempts.states = map[LoginSubject]LoginState{}
+	assert.Equal(t, http.StatusUnauthorized, login("alice@example.com", "wrong").Code)
+	assert.Equal(t, http.StatusOK, login("alice@example.com", "right").Code)
+	assert.Zero(t, attempts.states[alice].Failures)
+	assert.Equal(t, 1, attempts.states[NewLoginSubject(LoginSubjectIP, "203.0.113.7")].Failures)
+
+	// Logins go on when the records are unavailable
+	attempts.err = errors.New("redis: connection refused")
+	assert.Equal(t, http.StatusOK, login("alice@example.com", "right").Code)
+}
+
+func TestLoginGuardParallelAttempts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const lockoutAfter = 4
+	guard := NewLoginGuard(newFakeLoginAttempts(), LoginGuardOptions{
+		IdentifierFields: []string{"identifier"},
+		FailureStatuses:  []int{http.StatusUnauthorized},
+		Identifier:       LoginPolicy{Window: time.Hour, LockoutAfter: lockoutAfter, Lockout: 15 * time.Minute},
+		IP:               LoginPolicy{Window: time.Hour, LockoutAfter: 100, Lockout: time.Hour},
+	})
+
+	var reached atomic.Int32
+	router := gin.New()
+	router.POST("/login", guard.Protect(), func(c *gin.Context) {
+		reached.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		c.Status(http.StatusUnauthorized)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < lockoutAfter+5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("POST", "/login", strings.NewReader(`{"identifier":"alice@example.com","password":"guess"}`))
+			req.RemoteAddr = "203.0.113.7:4000"
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, int(reached.Load()), lockoutAfter)
+}
+
+func TestRedisLoginAttempts(t *testing.T) {
+	rdb := testRedis(t)
+	attempts := NewRedisLoginAttempts(rdb)
+	ctx := context.Background()
+
+	ip := NewLoginSubject(LoginSubjectIP, "203.0.113.7")
+	alice := NewLoginSubject(LoginSubjectIdentifier, "alice@example.com")
+	subjects := []LoginSubject{ip, alice}
+	policies := []LoginPolicy{
+		{Window: time.Hour, LockoutAfter: 100, Lockout: time.Hour},
+		{Window: time.Hour, DelayAfter: 1, Delay: time.Second, MaxDelay: 4 * time.Second, LockoutAfter: 3, Lockout: 15 * time.Minute},
+	}
+	now := time.UnixMilli(1760000000000)
+
+	allowed, states, err := attempts.Attempt(ctx, subjects, policies, now)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, LoginState{Failures: 1, LastFailure: now}, states[1])
+
+	// Delays double from the last failure
+	allowed, states, err = attempts.Attempt(ctx, subjects, policies, now.Add(500*time.Millisecond))
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 1, states[0].Failures, "refused attempts are not counted")
+	now = now.Add(time.Second)
+	allowed, _, err = attempts.Attempt(ctx, subjects, policies, now)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	now = now.Add(2 * time.Second)
+	allowed, states, err = attempts.Attempt(ctx, subjects, policies, now)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, 3, states[1].Failures)
+
+	// A success gives the attempt back
+	require.NoError(t, attempts.Refund(ctx, ip))
+	count, err := rdb.HGet(ctx, loginFailuresPrefix+ip.String(), "count").Int()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// The next attempt past the threshold locks the account out
+	now = now.Add(time.Minute)
+	allowed, states, err = attempts.Attempt(ctx, subjects, policies, now)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, LoginState{LockedUntil: now.Add(15 * time.Minute), LockStarted: true}, states[1])
+	ttl, err := rdb.PTTL(ctx, loginLockoutPrefix+alice.String()).Result()
+	require.NoError(t, err)
+	assert.InDelta(t, float64(15*time.Minute), float64(ttl), float64(time.Second))
+
+	allowed, states, err = attempts.Attempt(ctx, subjects, policies, now)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.False(t, states[1].LockStarted)
+
+	lockouts, err := attempts.Lockouts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []LoginLockout{{LoginSubject: alice, LockedUntil: now.Add(15 * time.Minute)}}, lockouts)
+	unlocked, err := attempts.Unlock(ctx, alice)
+	require.NoError(t, err)
+	assert.True(t, unlocked)
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeFetcher answers lookups from a function and counts the calls
+type fakeFetcher struct {
+	fetch func(params map[string]string) (interface{}, error)
+	calls int
+}
+
+func (f *fakeFetcher) Fetch(ctx context.Context, params map[string]string, header http.Header) (interface{}, error) {
+	f.calls++
+	return f.fetch(params)
+}
+
+func TestJSONValues(t *testing.T) {
+	document := map[string]interface{}{
+		"properties": []interface{}{
+			map[string]interface{}{"id": "p-1"},
+			map[string]interface{}{"id": float64(42)},
+			map[string]interface{}{"name": "no id"},
+		},
+		"owner": map[string]interface{}{"id": "user-1"},
+	}
+
+	assert.Equal(t, []string{"p-1", "42"}, jsonValues(document, "properties.id"))
+	assert.Equal(t, []string{"user-1"}, jsonValues(document, "owner.id"))
+	assert.Empty(t, jsonValues(document, "missing.id"))
+	assert.Empty(t, jsonValues(nil, "owner.id"))
+}
+
+func TestRequireOwnership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	caretakerProperties := &fakeFetcher{fetch: func(params map[string]string) (interface{}, error) {
+		if params["user_id"] == "caretaker-1" {
+			return map[string]interface{}{"properties": []interface{}{map[string]interface{}{"id": "p-1"}}}, nil
+		}
+		return map[string]interface{}{"properties": []interface{}{}}, nil
+	}}
+	propertyTenant := &fakeFetcher{fetch: func(params map[string]string) (interface{}, error) {
+		switch params["resource_id"] {
+		case "p-1":
+			return map[string]interface{}{"current_tenant_id": "tenant-1"}, nil
+		case "p-down":
+			return nil, errors.New("property-service: lookup answered 503")
+		}
+		return nil, nil
+	}}
+
+	rules := []OwnershipRule{
+		{Roles: []string{"caretaker"}, Lookup: NewOwnershipLookup("caretaker-properties", caretakerProperties, "properties.id", "", time.Minute)},
+		{Roles: []string{"tenant"}, Lookup: NewOwnershipLookup("property-tenant", propertyTenant, "", "current_tenant_id", time.Minute)},
+	}
+
+	tests := []struct {
+		name           string
+		userID         string
+		role           string
+		property       string
+		expectedStatus int
+	}{
+		{"Caretaker managing the property", "caretaker-1", "caretaker", "p-1", http.StatusOK},
+		{"Other caretaker", "caretaker-2", "caretaker", "p-1", http.StatusForbidden},
+		{"Tenant renting the property", "tenant-1", "tenant", "p-1", http.StatusOK},
+		{"Other tenant", "tenant-2", "tenant", "p-1", http.StatusForbidden},
+		{"Missing property", "tenant-1", "tenant", "p-missing", http.StatusForbidden},
+		{"Role without a rule", "caretaker-1", "guest", "p-1", http.StatusForbidden},
+		{"Bypass role", "admin-1", "admin", "p-9", http.StatusOK},
+		{"Lookup failure", "tenant-1", "tenant", "p-down", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/properties/:id", func(c *gin.Context) {
+				c.Set("user_id", tt.userID)
+				c.Set("user_role", tt.role)
+			}, RequireOwnership("id", []string{"admin"}, rules), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest("GET", "/properties/"+tt.property, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+
+	// Per-user answers are cached for every resource the user asks about
+	calls := caretakerProperties.calls
+	router := gin.New()
+	router.GET("/properties/:id", func(c *gin.Context) {
+		c.Set("user_id", "caretaker-1")
+		c.Set("user_role", "caretaker")
+	}, RequireOwnership("id", nil, rules), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	for _, property := range []string{"p-1", "p-2", "p-1"} {
+		req, _ := http.NewRequest("GET", "/properties/"+property, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	assert.Equal(t, calls, caretakerProperties.calls)
+}
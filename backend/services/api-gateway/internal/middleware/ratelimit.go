@@ -2,48 +2,332 @@ package middleware
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"math"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"baribhara/api-gateway/internal/config"
+
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// rateLimitPrefix namespaces rate limit state in Redis
+const rateLimitPrefix = "rate_limit:"
+
+// Rate limit headers on every limited response
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// gcraScript checks and takes a request's cost in one step with the generic
+// cell rate algorithm, a token bucket that stores a single timestamp: the
+// theoretical arrival time (TAT) at which the bucket is full again. Time comes
+// from Redis so gateway replicas with skewed clocks agree. Refused requests
+// change nothing.
+//
+//	KEYS[1]  the TAT, in microseconds
+//	ARGV[1]  emission interval, microseconds per request
+//	ARGV[2]  burst, the most requests allowed at once
+//	ARGV[3]  cost of this request, in requests
+//
+// It returns {allowed, remaining, retry after µs, reset after µs}.
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+
+local interval = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2]) * interval
+local cost = tonumber(ARGV[3]) * interval
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local tat = tonumber(redis.call("GET", KEYS[1])) or now
+if tat < now then
+	tat = now
+end
+
+local allow_at = tat + cost - capacity
+if now < allow_at then
+	local remaining = math.max(0, math.floor((capacity - (tat - now)) / interval))
+	return {0, remaining, allow_at - now, tat - now}
+end
+
+local new_tat = tat + cost
+redis.call("SET", KEYS[1], string.format("%.0f", new_tat), "PX", math.ceil((new_tat - now) / 1000))
+return {1, math.floor((capacity - (new_tat - now)) / interval), 0, new_tat - now}
+`)
+
+// Limit allows Requests per Period, in bursts of up to Burst requests
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// interval is the time it takes to earn back one request, in microseconds
+func (l Limit) interval() int64 {
+	interval := int64(math.Ceil(float64(l.Period.Microseconds()) / float64(l.Requests)))
+	if interval < 1 {
+		interval = 1
+	}
+	return interval
+}
+
+// burst defaults to Requests
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// LimitResult is a limiter's decision on one request
+type LimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+
+	// RetryAfter is how long a refused request should wait; ResetAfter is how
+	// long until the full burst is available again
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+// Limiter takes a request's cost from the allowance of a key. An error means
+// no decision could be made.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit, cost int) (*LimitResult, error)
+}
+
+// RedisLimiter keeps rate limits in Redis, shared by every gateway replica
+type RedisLimiter struct {
+	redis *redis.Client
+}
+
+// NewRedisLimiter creates a limiter backed by rdb
+func NewRedisLimiter(rdb *redis.Client) *RedisLimiter {
+	return &RedisLimiter{redis: rdb}
+}
+
+// Allow implements Limiter
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit Limit, cost int) (*LimitResult, error) {
+	values, err := gcraScript.Run(ctx, l.redis, []string{rateLimitPrefix + key},
+		limit.interval(), limit.burst(), cost).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	return &LimitResult{
+		Allowed:    values[0] == 1,
+		Limit:      limit.burst(),
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+		ResetAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
+
+// Rate limit key parts
+const (
+	RateLimitKeyIP     = "ip"
+	RateLimitKeyUser   = "user"
+	RateLimitKeyAPIKey = "api_key"
+)
+
+// RateLimitPolicy limits each caller, as identified by the Key parts, to
+// Limit, or to the most generous of Roles that the caller holds. Parts a
+// request lacks are left out; a request with none is keyed by client IP.
+type RateLimitPolicy struct {
+	Name  string
+	Key   []string
+	Limit Limit
+	Roles map[string]Limit
+}
+
+// limitFor picks the limit for the caller's roles
+func (p *RateLimitPolicy) limitFor(roles []string) Limit {
+	limit, found := p.Limit, false
+	for _, role := range roles {
+		roleLimit, ok := p.Roles[role]
+		if !ok {
+			continue
+		}
+		if !found || roleLimit.interval() < limit.interval() {
+			limit, found = roleLimit, true
+		}
+	}
+	return limit
+}
+
+// Rate limiter failure modes, for when the limiter cannot decide
+const (
+	RateLimitFailOpen   = "open"
+	RateLimitFailLocal  = "local"
+	RateLimitFailClosed = "closed"
 )
 
-// RateLimit implements rate limiting using Redis
-func RateLimit(rdb *redis.Client) gin.HandlerFunc {
+// RateLimiterOptions configure a RateLimiter. While the limiter fails,
+// FailureMode lets requests through ("open"), limits them in memory with each
+// of Replicas gateway replicas enforcing its share of every limit ("local"),
+// or refuses them ("closed").
+type RateLimiterOptions struct {
+	ExemptCIDRs  []string
+	APIKeyHeader string
+	FailureMode  string
+	Replicas     int
+	Logger       *zap.Logger
+}
+
+// RateLimiter applies rate limit policies to routes
+type RateLimiter struct {
+	limiter      Limiter
+	exempt       []*net.IPNet
+	apiKeyHeader string
+
+	failureMode string
+	local       *LocalLimiter
+	logger      *zap.Logger
+
+	// degraded is set while the limiter fails; retryAt is when, in Unix
+	// nanoseconds, it is next tried
+	degraded atomic.Bool
+	retryAt  atomic.Int64
+}
+
+// NewRateLimiter creates a rate limiter that never limits clients in the
+// exempt IPs or CIDRs and reads API keys from the APIKeyHeader
+func NewRateLimiter(limiter Limiter, opts RateLimiterOptions) (*RateLimiter, error) {
+	r := &RateLimiter{
+		limiter:      limiter,
+		apiKeyHeader: opts.APIKeyHeader,
+		failureMode:  opts.FailureMode,
+		local:        NewLocalLimiter(opts.Replicas),
+		logger:       opts.Logger,
+	}
+	if r.logger == nil {
+		r.logger = zap.NewNop()
+	}
+
+	exempt, err := config.ParseCIDRs(opts.ExemptCIDRs)
+	if err != nil {
+		return nil, fmt.Errorf("exempt CIDR: %w", err)
+	}
+	r.exempt = exempt
+
+	return r, nil
+}
+
+// Limit counts each request against policy at cost requests
+func (r *RateLimiter) Limit(policy *RateLimitPolicy, cost int) gin.HandlerFunc {
+	if cost < 1 {
+		cost = 1
+	}
+
 	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
-		key := fmt.Sprintf("rate_limit:%s", clientIP)
-		
-		// Get current count
-		count, err := rdb.Get(context.Background(), key).Int()
-		if err != nil && err != redis.Nil {
+		if r.exempted(c.ClientIP()) {
 			c.Next()
 			return
 		}
 
-		// Check if limit exceeded (100 requests per minute)
-		if count >= 100 {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error": "Rate limit exceeded",
-				"retry_after": 60,
-			})
+		limit := policy.limitFor(contextRoles(c))
+		result, err := r.allow(c.Request.Context(), r.key(c, policy), limit, cost)
+		if err != nil {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(limiterRetryInterval)))
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Rate limiting unavailable", "code": "RATE_LIMIT_UNAVAILABLE"})
 			c.Abort()
 			return
 		}
-
-		// Increment counter
-		pipe := rdb.Pipeline()
-		pipe.Incr(context.Background(), key)
-		pipe.Expire(context.Background(), key, time.Minute)
-		_, err = pipe.Exec(context.Background())
-		if err != nil {
+		if result == nil {
 			c.Next()
 			return
 		}
 
+		setRateLimitHeaders(c, result)
+		if !result.Allowed {
+			rejectRateLimited(c, result)
+			return
+		}
+
 		c.Next()
 	}
 }
+
+func (r *RateLimiter) exempted(clientIP string) bool {
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return false
+	}
+	for _, network := range r.exempt {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// key names the caller's allowance under a policy, e.g. "default:user=42".
+// API keys are hashed so they never reach Redis.
+func (r *RateLimiter) key(c *gin.Context, policy *RateLimitPolicy) string {
+	var parts []string
+	for _, part := range policy.Key {
+		var value string
+		switch part {
+		case RateLimitKeyIP:
+			value = c.ClientIP()
+		case RateLimitKeyUser:
+			value = contextString(c, "user_id")
+		case RateLimitKeyAPIKey:
+			if apiKey := c.GetHeader(r.apiKeyHeader); apiKey != "" {
+				sum := sha256.Sum256([]byte(apiKey))
+				value = hex.EncodeToString(sum[:16])
+			}
+		}
+		if value != "" {
+			parts = append(parts, part+"="+value)
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, RateLimitKeyIP+"="+c.ClientIP())
+	}
+
+	return policy.Name + ":" + strings.Join(parts, ",")
+}
+
+// setRateLimitHeaders reports the limit, what is left of it, and the seconds
+// until it is fully available again
+func setRateLimitHeaders(c *gin.Context, result *LimitResult) {
+	c.Header(RateLimitLimitHeader, strconv.Itoa(result.Limit))
+	c.Header(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+	c.Header(RateLimitResetHeader, strconv.Itoa(ceilSeconds(result.ResetAfter)))
+}
+
+// rejectRateLimited refuses a request until it may be retried
+func rejectRateLimited(c *gin.Context, result *LimitResult) {
+	retryAfter := ceilSeconds(result.RetryAfter)
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       "Rate limit exceeded",
+		"code":        "RATE_LIMITED",
+		"retry_after": retryAfter,
+	})
+	c.Abort()
+}
+
+// ceilSeconds rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
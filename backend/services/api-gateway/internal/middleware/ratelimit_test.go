@@ -0,0 +1,301 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLimiter stands in for Redis with a LocalLimiter on a controlled clock
+type fakeLimiter struct {
+	*LocalLimiter
+	now   time.Time
+	err   error
+	calls int
+}
+
+func newFakeLimiter() *fakeLimiter {
+	f := &fakeLimiter{LocalLimiter: NewLocalLimiter(1), now: time.Now()}
+	f.LocalLimiter.now = func() time.Time { return f.now }
+	return f
+}
+
+func (f *fakeLimiter) Allow(ctx context.Context, key string, limit Limit, cost int) (*LimitResult, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.LocalLimiter.Allow(ctx, key, limit, cost)
+}
+
+func (f *fakeLimiter) has(key string) bool {
+	_, ok := f.tats.get(key)
+	return ok
+}
+
+func TestLimitInterval(t *testing.T) {
+	assert.Equal(t, int64(600000), Limit{Requests: 100, Period: time.Minute}.interval())
+	assert.Equal(t, int64(8571429), Limit{Requests: 7, Period: time.Minute}.interval())
+	assert.Equal(t, int64(1), Limit{Requests: 10, Period: time.Microsecond}.interval())
+	assert.Equal(t, 100, Limit{Requests: 100}.burst())
+	assert.Equal(t, 5, Limit{Requests: 100, Burst: 5}.burst())
+}
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := newFakeLimiter()
+	rateLimiter, err := NewRateLimiter(limiter, RateLimiterOptions{APIKeyHeader: "X-API-Key", FailureMode: RateLimitFailOpen})
+	assert.NoError(t, err)
+	policy := &RateLimitPolicy{Name: "default", Key: []string{RateLimitKeyIP}, Limit: Limit{Requests: 3, Period: time.Minute}}
+
+	router := gin.New()
+	router.Use(rateLimiter.Limit(policy, 1))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func() *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "203.0.113.7:4000"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, remaining := range []string{"2", "1", "0"} {
+		w := request()
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "3", w.Header().Get(RateLimitLimitHeader))
+		assert.Equal(t, remaining, w.Header().Get(RateLimitRemainingHeader))
+	}
+
+	w := request()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get(RateLimitRemainingHeader))
+	assert.Equal(t, "60", w.Header().Get(RateLimitResetHeader))
+	assert.Equal(t, "20", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "RATE_LIMITED")
+
+	// Refused requests do not push the reset further out
+	w = request()
+	assert.Equal(t, "20", w.Header().Get("Retry-After"))
+
+	limiter.now = limiter.now.Add(20 * time.Second)
+	w = request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get(RateLimitRemainingHeader))
+
+	// Without a decision the request goes through
+	limiter.err = errors.New("redis: connection refused")
+	w = request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(RateLimitLimitHeader))
+}
+
+func TestRateLimitPolicies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := newFakeLimiter()
+	rateLimiter, err := NewRateLimiter(limiter, RateLimiterOptions{
+		ExemptCIDRs:  []string{"10.0.0.0/8", "192.0.2.1"},
+		APIKeyHeader: "X-API-Key",
+	})
+	assert.NoError(t, err)
+
+	policy := &RateLimitPolicy{
+		Name:  "default",
+		Key:   []string{RateLimitKeyAPIKey, RateLimitKeyUser},
+		Limit: Limit{Requests: 10, Period: time.Minute},
+		Roles: map[string]Limit{
+			"caretaker": {Requests: 20, Period: time.Minute},
+			"admin":     {Requests: 100, Period: time.Minute},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		remoteIP  string
+		userID    string
+		role      string
+		apiKey    string
+		cost      int
+		key       string
+		limit     string
+		remaining string
+	}{
+		{"Anonymous callers fall back to their IP", "203.0.113.7", "", "", "", 1, "default:ip=203.0.113.7", "10", "9"},
+		{"Users behind one IP are counted apart", "203.0.113.7", "user-1", "tenant", "", 1, "default:user=user-1", "10", "9"},
+		{"Role limit", "203.0.113.7", "user-2", "caretaker", "", 1, "default:user=user-2", "20", "19"},
+		{"API key and user combined", "203.0.113.7", "user-3", "tenant", "key-1", 1, "", "10", "9"},
+		{"Weighted cost", "203.0.113.8", "user-4", "tenant", "", 5, "default:user=user-4", "10", "5"},
+		{"Exempt CIDR", "10.1.2.3", "user-5", "tenant", "", 1, "", "", ""},
+		{"Exempt IP", "192.0.2.1", "", "", "", 1, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/test", func(c *gin.Context) {
+				if tt.userID != "" {
+					c.Set("user_id", tt.userID)
+					c.Set("user_role", tt.role)
+				}
+			}, rateLimiter.Limit(policy, tt.cost), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest("GET", "/test", nil)
+			req.RemoteAddr = tt.remoteIP + ":4000"
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.limit, w.Header().Get(RateLimitLimitHeader))
+			assert.Equal(t, tt.remaining, w.Header().Get(RateLimitRemainingHeader))
+			if tt.key != "" {
+				assert.True(t, limiter.has(tt.key), tt.key)
+			}
+		})
+	}
+
+	// API keys never appear in limiter keys
+	for key := range limiter.tats.entries {
+		assert.NotContains(t, key, "key-1")
+	}
+}
+
+func TestRateLimitFailureModes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	policy := &RateLimitPolicy{Name: "default", Key: []string{RateLimitKeyIP}, Limit: Limit{Requests: 4, Period: time.Minute}}
+	request := func(router *gin.Engine) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "203.0.113.7:4000"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	setup := func(mode string) (*fakeLimiter, *RateLimiter, *gin.Engine) {
+		limiter := newFakeLimiter()
+		limiter.err = errors.New("redis: connection refused")
+		rateLimiter, err := NewRateLimiter(limiter, RateLimiterOptions{FailureMode: mode, Replicas: 2})
+		assert.NoError(t, err)
+
+		router := gin.New()
+		router.GET("/test", rateLimiter.Limit(policy, 1), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return limiter, rateLimiter, router
+	}
+
+	t.Run("Open", func(t *testing.T) {
+		_, _, router := setup(RateLimitFailOpen)
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, http.StatusOK, request(router).Code)
+		}
+	})
+
+	t.Run("Local limits each replica to its share", func(t *testing.T) {
+		_, _, router := setup(RateLimitFailLocal)
+		assert.Equal(t, http.StatusOK, request(router).Code)
+		w := request(router)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get(RateLimitLimitHeader))
+		assert.Equal(t, http.StatusTooManyRequests, request(router).Code)
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		_, _, router := setup(RateLimitFailClosed)
+		w := request(router)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), "RATE_LIMIT_UNAVAILABLE")
+	})
+
+	t.Run("Redis is retried after an interval", func(t *testing.T) {
+		limiter, rateLimiter, router := setup(RateLimitFailClosed)
+		request(router)
+		request(router)
+		assert.Equal(t, 1, limiter.calls)
+		assert.True(t, rateLimiter.degraded.Load())
+
+		limiter.err = nil
+		rateLimiter.retryAt.Store(0)
+		assert.Equal(t, http.StatusOK, request(router).Code)
+		assert.False(t, rateLimiter.degraded.Load())
+	})
+}
+
+func TestRedisLimiter(t *testing.T) {
+	rdb := testRedis(t)
+	limiter := NewRedisLimiter(rdb)
+	ctx := context.Background()
+
+	// One request per 100ms with a burst of 3
+	limit := Limit{Requests: 10, Period: time.Second, Burst: 3}
+	const slack = 50 * time.Millisecond
+
+	for i := 1; i <= 3; i++ {
+		result, err := limiter.Allow(ctx, "client", limit, 1)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, 3-i, result.Remaining)
+		assert.Zero(t, result.RetryAfter)
+		assert.InDelta(t, time.Duration(i)*100*time.Millisecond, result.ResetAfter, float64(slack))
+	}
+
+	// The key lives until the burst has refilled
+	ttl, err := rdb.PTTL(ctx, rateLimitPrefix+"client").Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+	assert.LessOrEqual(t, ttl, 300*time.Millisecond)
+
+	result, err := limiter.Allow(ctx, "client", limit, 1)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Zero(t, result.Remaining)
+	assert.InDelta(t, 100*time.Millisecond, result.RetryAfter, float64(slack))
+	assert.InDelta(t, 300*time.Millisecond, result.ResetAfter, float64(slack))
+
+	// Refused requests leave the allowance alone, so one interval refills one
+	time.Sleep(110 * time.Millisecond)
+	result, err = limiter.Allow(ctx, "client", limit, 1)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Zero(t, result.Remaining)
+	result, err = limiter.Allow(ctx, "client", limit, 1)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+
+	// Once the key expires the full burst is back, and a cost beyond it never fits
+	time.Sleep(350 * time.Millisecond)
+	exists, err := rdb.Exists(ctx, rateLimitPrefix+"client").Result()
+	require.NoError(t, err)
+	assert.Zero(t, exists)
+
+	result, err = limiter.Allow(ctx, "client", limit, 4)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 3, result.Remaining)
+	result, err = limiter.Allow(ctx, "client", limit, 3)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Zero(t, result.Remaining)
+
+	// Keys are independent
+	result, err = limiter.Allow(ctx, "other", limit, 1)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+}
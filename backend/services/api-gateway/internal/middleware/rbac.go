@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleHierarchy maps each role to every role it includes, directly or through
+// other roles, e.g. super_admin -> [admin]
+type RoleHierarchy map[string][]string
+
+// NewRoleHierarchy resolves the roles each role directly includes into the
+// full set it implies. Cycles are harmless: roles in a cycle include each other.
+func NewRoleHierarchy(includes map[string][]string) RoleHierarchy {
+	hierarchy := make(RoleHierarchy)
+
+	for role := range includes {
+		seen := map[string]bool{role: true}
+		queue := append([]string{}, includes[role]...)
+		for len(queue) > 0 {
+			next := queue[0]
+			queue = queue[1:]
+			if seen[next] {
+				continue
+			}
+			seen[next] = true
+			hierarchy[role] = append(hierarchy[role], next)
+			queue = append(queue, includes[next]...)
+		}
+		sort.Strings(hierarchy[role])
+	}
+
+	return hierarchy
+}
+
+// Expand returns the roles together with every role they include, without
+// duplicates and in the order first met
+func (h RoleHierarchy) Expand(roles []string) []string {
+	seen := make(map[string]bool)
+	expanded := make([]string, 0, len(roles))
+	add := func(role string) {
+		if role != "" && !seen[role] {
+			seen[role] = true
+			expanded = append(expanded, role)
+		}
+	}
+
+	for _, role := range roles {
+		add(role)
+		for _, included := range h[role] {
+			add(included)
+		}
+	}
+	return expanded
+}
+
+// claimRoles reads a roles claim, either a JSON array or a comma-separated
+// string
+func claimRoles(claim interface{}) []string {
+	var roles []string
+	switch value := claim.(type) {
+	case []interface{}:
+		for _, v := range value {
+			if role, ok := v.(string); ok {
+				roles = append(roles, role)
+			}
+		}
+	case []string:
+		roles = append(roles, value...)
+	case string:
+		for _, role := range strings.Split(value, ",") {
+			roles = append(roles, strings.TrimSpace(role))
+		}
+	}
+	return roles
+}
+
+// hasAnyRole reports whether the caller holds one of the roles
+func hasAnyRole(c *gin.Context, required []string) bool {
+	for _, role := range contextRoles(c) {
+		for _, r := range required {
+			if role == r {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RequireAnyRole restricts access to users holding at least one of the roles,
+// directly or through the role hierarchy applied at authentication
+func RequireAnyRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasAnyRole(c, roles) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions", "code": "FORBIDDEN"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
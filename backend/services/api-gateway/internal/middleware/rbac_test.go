@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleHierarchy(t *testing.T) {
+	hierarchy := NewRoleHierarchy(map[string][]string{
+		"super_admin": {"admin"},
+		"admin":       {"support"},
+	})
+
+	assert.Equal(t, []string{"admin", "support"}, hierarchy["super_admin"])
+	assert.Equal(t, []string{"super_admin", "admin", "support"}, hierarchy.Expand([]string{"super_admin"}))
+	assert.Equal(t, []string{"tenant", "caretaker"}, hierarchy.Expand([]string{"tenant", "caretaker", "tenant"}))
+}
+
+func TestRequireAnyRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	verifier := NewJWTVerifier("test-secret", JWTOptions{
+		RolesClaim: "roles",
+		Hierarchy:  NewRoleHierarchy(map[string][]string{"super_admin": {"admin"}}),
+	})
+
+	tests := []struct {
+		name           string
+		claims         jwt.MapClaims
+		required       []string
+		expectedStatus int
+	}{
+		{
+			name:           "Single role claim",
+			claims:         jwt.MapClaims{"role": "caretaker"},
+			required:       []string{"caretaker"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Dual roles from the roles claim",
+			claims:         jwt.MapClaims{"roles": []string{"tenant", "caretaker"}},
+			required:       []string{"caretaker"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Role implied by the hierarchy",
+			claims:         jwt.MapClaims{"role": "super_admin"},
+			required:       []string{"admin"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Hierarchy does not work upwards",
+			claims:         jwt.MapClaims{"roles": []string{"admin"}},
+			required:       []string{"super_admin"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Tenant cannot act as caretaker",
+			claims:         jwt.MapClaims{"role": "tenant"},
+			required:       []string{"caretaker"},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.claims["sub"] = "user-1"
+			tt.claims["exp"] = time.Now().Add(time.Hour).Unix()
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tt.claims).SignedString([]byte("test-secret"))
+			if err != nil {
+				t.Fatalf("Failed to create token: %v", err)
+			}
+
+			router := gin.New()
+			router.GET("/test", JWTAuth(verifier), RequireAnyRole(tt.required...), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
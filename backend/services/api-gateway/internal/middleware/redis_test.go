@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+// testRedis connects to the Redis server at REDIS_ADDR, skipping the test
+// when it is unset. Tests get database 15, emptied first.
+func testRedis(t *testing.T) *redis.Client {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR is not set")
+	}
+
+	rdb := redis.NewClient(&redis.Options{Addr: addr, DB: 15})
+	t.Cleanup(func() { rdb.Close() })
+	require.NoError(t, rdb.FlushDB(context.Background()).Err())
+	return rdb
+}
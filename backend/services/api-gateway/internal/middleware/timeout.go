@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestTimeoutHeader lets clients ask for a deadline, in milliseconds or as a
+// Go duration ("2500", "2.5s"). The gateway forwards the time remaining to
+// upstreams in the same header.
+const RequestTimeoutHeader = "X-Request-Timeout"
+
+// writeGrace is how long past its deadline a request may take to write its
+// response, such as the 504 for a timed out upstream
+const writeGrace = time.Second
+
+// RequestTimeout puts a deadline on the request context. A client-supplied
+// X-Request-Timeout replaces the default but is capped at max; when max is
+// zero the default is also the cap. The connection's write deadline follows
+// the request's, so routes may outlast the server's WriteTimeout.
+func RequestTimeout(timeout, max time.Duration) gin.HandlerFunc {
+	if max <= 0 {
+		max = timeout
+	}
+
+	return func(c *gin.Context) {
+		deadline := timeout
+		if requested, ok := parseRequestTimeout(c.GetHeader(RequestTimeoutHeader)); ok {
+			deadline = requested
+			if max > 0 && deadline > max {
+				deadline = max
+			}
+		}
+
+		if deadline <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), deadline)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		// Writers that cannot take a deadline keep the server's
+		_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(deadline + writeGrace))
+		c.Next()
+	}
+}
+
+// parseRequestTimeout accepts whole milliseconds or a Go duration string
+func parseRequestTimeout(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(ms) * time.Millisecond, ms > 0
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return d, d > 0
+	}
+	return 0, false
+}
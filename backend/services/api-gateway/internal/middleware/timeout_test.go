@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		timeout       time.Duration
+		max           time.Duration
+		header        string
+		expectedLimit time.Duration
+	}{
+		{
+			name:          "Route default",
+			timeout:       2 * time.Second,
+			expectedLimit: 2 * time.Second,
+		},
+		{
+			name:          "Client asks for less",
+			timeout:       2 * time.Second,
+			header:        "500",
+			expectedLimit: 500 * time.Millisecond,
+		},
+		{
+			name:          "Client asks for more than the default",
+			timeout:       2 * time.Second,
+			max:           time.Minute,
+			header:        "30s",
+			expectedLimit: 30 * time.Second,
+		},
+		{
+			name:          "Client request is capped",
+			timeout:       2 * time.Second,
+			max:           time.Minute,
+			header:        "10m",
+			expectedLimit: time.Minute,
+		},
+		{
+			name:          "Default is the cap without a max",
+			timeout:       2 * time.Second,
+			header:        "5000",
+			expectedLimit: 2 * time.Second,
+		},
+		{
+			name:          "Invalid header is ignored",
+			timeout:       2 * time.Second,
+			header:        "soon",
+			expectedLimit: 2 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var limit time.Duration
+			router := gin.New()
+			router.Use(RequestTimeout(tt.timeout, tt.max))
+			router.GET("/test", func(c *gin.Context) {
+				deadline, ok := c.Request.Context().Deadline()
+				assert.True(t, ok)
+				limit = time.Until(deadline)
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest("GET", "/test", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestTimeoutHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.InDelta(t, float64(tt.expectedLimit), float64(limit), float64(100*time.Millisecond))
+		})
+	}
+}
+
+func TestRequestTimeoutExtendsWriteDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestTimeout(2*time.Second, 0))
+	router.GET("/slow", func(c *gin.Context) {
+		time.Sleep(300 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	})
+
+	server := httptest.NewUnstartedServer(router)
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/slow")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "done", string(body))
+}
@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenVerdictPrefix namespaces cached verdicts in Redis. Keys hold a hash of
+// the token, never the token itself.
+const tokenVerdictPrefix = "token_verdict:"
+
+// maxLocalVerdicts bounds the in-memory verdict cache
+const maxLocalVerdicts = 10000
+
+var tokenValidations = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gateway_token_validations_total",
+		Help: "Bearer token validations by where the verdict came from and what it was",
+	},
+	[]string{"source", "result"},
+)
+
+// TokenVerdict is an authority's answer on whether a bearer token is valid
+type TokenVerdict struct {
+	Valid     bool      `json:"valid"`
+	UserID    string    `json:"user_id,omitempty"`
+	Role      string    `json:"role,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+func (v *TokenVerdict) result() string {
+	if v.Valid {
+		return "valid"
+	}
+	return "invalid"
+}
+
+// TokenValidator asks an authority whether a token is valid. An error means no
+// verdict could be had, not that the token is invalid.
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (*TokenVerdict, error)
+}
+
+// CachedTokenValidator remembers the verdicts of another validator for a short
+// TTL, in memory and in Redis so that gateway replicas share them. Verdicts on
+// valid tokens never outlive the token.
+type CachedTokenValidator struct {
+	next  TokenValidator
+	redis *redis.Client
+	ttl   time.Duration
+	local *localCache[*TokenVerdict]
+}
+
+// NewCachedTokenValidator caches the verdicts of next for ttl. rdb may be nil
+// to cache in memory only; a ttl of zero disables caching.
+func NewCachedTokenValidator(next TokenValidator, rdb *redis.Client, ttl time.Duration) *CachedTokenValidator {
+	return &CachedTokenValidator{
+		next:  next,
+		redis: rdb,
+		ttl:   ttl,
+		local: newLocalCache[*TokenVerdict](maxLocalVerdicts),
+	}
+}
+
+// ShareCache makes v answer from the in-memory cache of previous, a validator
+// with the same settings that v replaces on reload
+func (v *CachedTokenValidator) ShareCache(previous *CachedTokenValidator) {
+	if previous != nil {
+		v.local = previous.local
+	}
+}
+
+// ValidateToken implements TokenValidator
+func (v *CachedTokenValidator) ValidateToken(ctx context.Context, token string) (*TokenVerdict, error) {
+	if v.ttl <= 0 {
+		return v.validate(ctx, token)
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+	now := time.Now()
+
+	if cached, ok := v.local.get(key); ok {
+		tokenValidations.WithLabelValues("memory", cached.result()).Inc()
+		return cached, nil
+	}
+
+	// Redis failures only cost a call to the authority
+	if v.redis != nil {
+		if data, err := v.redis.Get(ctx, tokenVerdictPrefix+key).Bytes(); err == nil {
+			verdict := &TokenVerdict{}
+			if json.Unmarshal(data, verdict) == nil {
+				tokenValidations.WithLabelValues("redis", verdict.result()).Inc()
+				v.local.set(key, verdict, v.expiry(verdict, now))
+				return verdict, nil
+			}
+		}
+	}
+
+	verdict, err := v.validate(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	expires := v.expiry(verdict, now)
+	v.local.set(key, verdict, expires)
+	if v.redis != nil {
+		if data, err := json.Marshal(verdict); err == nil {
+			v.redis.Set(ctx, tokenVerdictPrefix+key, data, expires.Sub(now))
+		}
+	}
+
+	return verdict, nil
+}
+
+func (v *CachedTokenValidator) validate(ctx context.Context, token string) (*TokenVerdict, error) {
+	verdict, err := v.next.ValidateToken(ctx, token)
+	if err != nil {
+		tokenValidations.WithLabelValues("authority", "error").Inc()
+		return nil, err
+	}
+	tokenValidations.WithLabelValues("authority", verdict.result()).Inc()
+	return verdict, nil
+}
+
+// expiry caps the cache TTL at the token's own expiry
+func (v *CachedTokenValidator) expiry(verdict *TokenVerdict, now time.Time) time.Time {
+	expires := now.Add(v.ttl)
+	if verdict.Valid && !verdict.ExpiresAt.IsZero() && verdict.ExpiresAt.Before(expires) {
+		expires = verdict.ExpiresAt
+	}
+	return expires
+}
+
+// RemoteJWTAuth authenticates requests with validator's verdict on the bearer
+// token, so logouts, suspensions and role changes take effect before the token
+// expires. A valid verdict stands in for the signature check only: the token
+// must still pass verifier's other checks and name the verdict's user. When no
+// verdict can be had, failOpen falls back to verifying the token locally with
+// verifier; otherwise the request is refused.
+func RemoteJWTAuth(verifier *JWTVerifier, validator TokenValidator, failOpen bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString, ok := bearerToken(c)
+		if !ok {
+			return
+		}
+
+		verdict, err := validator.ValidateToken(c.Request.Context(), tokenString)
+		if err != nil {
+			if !failOpen {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Authentication service unavailable", "code": "AUTH_UNAVAILABLE"})
+				c.Abort()
+				return
+			}
+
+			claims, err := verifier.Verify(c.Request.Context(), tokenString)
+			if err != nil {
+				tokenValidations.WithLabelValues("local_fallback", "invalid").Inc()
+				rejectToken(c, err)
+				return
+			}
+			tokenValidations.WithLabelValues("local_fallback", "valid").Inc()
+
+			verifier.setIdentity(c, claims, claims["sub"], claims["role"])
+			c.Next()
+			return
+		}
+
+		if !verdict.Valid {
+			rejectToken(c, errTokenInvalid)
+			return
+		}
+
+		claims, err := verifier.verifyVouched(c.Request.Context(), tokenString)
+		if err != nil {
+			rejectToken(c, err)
+			return
+		}
+		if sub, _ := claims["sub"].(string); verdict.UserID != "" && sub != verdict.UserID {
+			// The token is not the one the verdict is about
+			rejectToken(c, errTokenInvalid)
+			return
+		}
+
+		verifier.setIdentity(c, claims, verdict.UserID, verdict.Role)
+
+		c.Next()
+	}
+}
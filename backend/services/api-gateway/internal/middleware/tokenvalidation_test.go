@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeValidator answers every token with the same verdict or error
+type fakeValidator struct {
+	verdict *TokenVerdict
+	err     error
+	calls   int
+}
+
+func (f *fakeValidator) ValidateToken(ctx context.Context, token string) (*TokenVerdict, error) {
+	f.calls++
+	return f.verdict, f.err
+}
+
+// signToken signs claims with the test secret
+func signToken(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+	return token
+}
+
+func TestRemoteJWTAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	unavailable := errors.New("connection refused")
+
+	tests := []struct {
+		name           string
+		authHeader     string
+		verdict        *TokenVerdict
+		err            error
+		failOpen       bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Valid token takes the verdict's identity",
+			authHeader:     "Bearer " + createValidToken(t),
+			verdict:        &TokenVerdict{Valid: true, UserID: "test-user-id", Role: "admin"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "test-user-id admin test@example.com",
+		},
+		{
+			name:           "Valid verdict on an expired token",
+			authHeader:     "Bearer " + signToken(t, jwt.MapClaims{"sub": "test-user-id", "exp": time.Now().Add(-time.Hour).Unix()}),
+			verdict:        &TokenVerdict{Valid: true, UserID: "test-user-id"},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "TOKEN_EXPIRED",
+		},
+		{
+			name:           "Valid verdict about another user",
+			authHeader:     "Bearer " + createValidToken(t),
+			verdict:        &TokenVerdict{Valid: true, UserID: "user-1", Role: "admin"},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "TOKEN_INVALID",
+		},
+		{
+			name:           "Token revoked by auth-service",
+			authHeader:     "Bearer " + createValidToken(t),
+			verdict:        &TokenVerdict{Valid: false, Reason: "logged out"},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "Invalid token",
+		},
+		{
+			name:           "Unreachable auth-service fails closed",
+			authHeader:     "Bearer " + createValidToken(t),
+			err:            unavailable,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "AUTH_UNAVAILABLE",
+		},
+		{
+			name:           "Unreachable auth-service fails open to local verification",
+			authHeader:     "Bearer " + createValidToken(t),
+			err:            unavailable,
+			failOpen:       true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "test-user-id user test@example.com",
+		},
+		{
+			name:           "Local verification still rejects bad tokens",
+			authHeader:     "Bearer invalid-token",
+			err:            unavailable,
+			failOpen:       true,
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "TOKEN_MALFORMED",
+		},
+		{
+			name:           "No Authorization header",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "Authorization header required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &fakeValidator{verdict: tt.verdict, err: tt.err}
+
+			router := gin.New()
+			router.Use(RemoteJWTAuth(NewJWTVerifier("test-secret", JWTOptions{}), validator, tt.failOpen))
+			router.GET("/test", func(c *gin.Context) {
+				c.String(http.StatusOK, "%v %v %v", c.MustGet("user_id"), c.MustGet("user_role"), c.MustGet("user_email"))
+			})
+
+			req, _ := http.NewRequest("GET", "/test", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestCachedTokenValidator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Verdicts are cached", func(t *testing.T) {
+		next := &fakeValidator{verdict: &TokenVerdict{Valid: true, UserID: "user-1"}}
+		validator := NewCachedTokenValidator(next, nil, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			verdict, err := validator.ValidateToken(ctx, "token")
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", verdict.UserID)
+		}
+		assert.Equal(t, 1, next.calls)
+
+		_, err := validator.ValidateToken(ctx, "other-token")
+		require.NoError(t, err)
+		assert.Equal(t, 2, next.calls)
+	})
+
+	t.Run("Errors are not cached", func(t *testing.T) {
+		next := &fakeValidator{err: errors.New("connection refused")}
+		validator := NewCachedTokenValidator(next, nil, time.Minute)
+
+		for i := 0; i < 2; i++ {
+			_, err := validator.ValidateToken(ctx, "token")
+			assert.Error(t, err)
+		}
+		assert.Equal(t, 2, next.calls)
+	})
+
+	t.Run("Verdicts do not outlive the token", func(t *testing.T) {
+		next := &fakeValidator{verdict: &TokenVerdict{Valid: true, ExpiresAt: time.Now().Add(-time.Second)}}
+		validator := NewCachedTokenValidator(next, nil, time.Minute)
+
+		for i := 0; i < 2; i++ {
+			_, err := validator.ValidateToken(ctx, "token")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, next.calls)
+	})
+
+	t.Run("A replacement shares the cache", func(t *testing.T) {
+		next := &fakeValidator{verdict: &TokenVerdict{Valid: true, UserID: "user-1"}}
+		previous := NewCachedTokenValidator(next, nil, time.Minute)
+		_, err := previous.ValidateToken(ctx, "token")
+		require.NoError(t, err)
+
+		validator := NewCachedTokenValidator(next, nil, time.Minute)
+		validator.ShareCache(previous)
+		verdict, err := validator.ValidateToken(ctx, "token")
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", verdict.UserID)
+		assert.Equal(t, 1, next.calls)
+	})
+
+	t.Run("Zero TTL disables caching", func(t *testing.T) {
+		next := &fakeValidator{verdict: &TokenVerdict{Valid: true}}
+		validator := NewCachedTokenValidator(next, nil, 0)
+
+		for i := 0; i < 2; i++ {
+			_, err := validator.ValidateToken(ctx, "token")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, next.calls)
+	})
+}
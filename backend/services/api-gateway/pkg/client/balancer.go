@@ -0,0 +1,228 @@
+package client
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"baribhara/api-gateway/internal/config"
+)
+
+// Balancing strategies supported in ServiceConfig.Balancer
+const (
+	RoundRobin         = "round_robin"
+	WeightedRoundRobin = "weighted_round_robin"
+	LeastOutstanding   = "least_outstanding"
+	ConsistentHash     = "consistent_hash"
+)
+
+// Endpoint is a single upstream instance of a service
+type Endpoint struct {
+	URL    *url.URL
+	Weight int
+
+	outstanding int64
+	unhealthy   int32
+}
+
+// newEndpoint builds an endpoint from its configuration
+func newEndpoint(cfg config.EndpointConfig) (*Endpoint, error) {
+	target, err := url.Parse(fmt.Sprintf("http://%s:%d", cfg.Host, cfg.Port))
+	if err != nil {
+		return nil, err
+	}
+
+	weight := cfg.Weight
+	if weight <= 0 {
+		weight = 1
+	}
+
+	return &Endpoint{URL: target, Weight: weight}, nil
+}
+
+// Outstanding returns the number of in-flight requests on the endpoint
+func (e *Endpoint) Outstanding() int64 {
+	return atomic.LoadInt64(&e.outstanding)
+}
+
+// Healthy reports whether the endpoint may receive traffic
+func (e *Endpoint) Healthy() bool {
+	return atomic.LoadInt32(&e.unhealthy) == 0
+}
+
+// setHealthy updates the health state and reports whether it changed
+func (e *Endpoint) setHealthy(healthy bool) bool {
+	var value int32
+	if !healthy {
+		value = 1
+	}
+	return atomic.SwapInt32(&e.unhealthy, value) != value
+}
+
+func (e *Endpoint) acquire() {
+	atomic.AddInt64(&e.outstanding, 1)
+}
+
+func (e *Endpoint) release() {
+	atomic.AddInt64(&e.outstanding, -1)
+}
+
+// Balancer picks the endpoint that serves the next request. The key is used by
+// affinity-based strategies and ignored by the others. Unhealthy endpoints are
+// skipped; Next returns nil when none is healthy.
+type Balancer interface {
+	Next(key string) *Endpoint
+}
+
+// NewBalancer creates a balancer for the given strategy
+func NewBalancer(strategy string, endpoints []*Endpoint) (Balancer, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no endpoints configured")
+	}
+
+	switch strategy {
+	case "", RoundRobin:
+		return &roundRobin{endpoints: endpoints}, nil
+	case WeightedRoundRobin:
+		return &weightedRoundRobin{endpoints: endpoints, current: make([]int, len(endpoints))}, nil
+	case LeastOutstanding:
+		return &leastOutstanding{endpoints: endpoints}, nil
+	case ConsistentHash:
+		return newHashRing(endpoints), nil
+	default:
+		return nil, fmt.Errorf("unknown balancer %q", strategy)
+	}
+}
+
+// roundRobin cycles through endpoints in order
+type roundRobin struct {
+	endpoints []*Endpoint
+	next      uint64
+}
+
+func (b *roundRobin) Next(string) *Endpoint {
+	for range b.endpoints {
+		n := atomic.AddUint64(&b.next, 1) - 1
+		if endpoint := b.endpoints[n%uint64(len(b.endpoints))]; endpoint.Healthy() {
+			return endpoint
+		}
+	}
+	return nil
+}
+
+// weightedRoundRobin implements smooth weighted round-robin, spreading
+// heavier endpoints evenly instead of sending them bursts
+type weightedRoundRobin struct {
+	mu        sync.Mutex
+	endpoints []*Endpoint
+	current   []int
+}
+
+func (b *weightedRoundRobin) Next(string) *Endpoint {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	total := 0
+	best := -1
+	for i, endpoint := range b.endpoints {
+		if !endpoint.Healthy() {
+			continue
+		}
+		b.current[i] += endpoint.Weight
+		total += endpoint.Weight
+		if best == -1 || b.current[i] > b.current[best] {
+			best = i
+		}
+	}
+	if best == -1 {
+		return nil
+	}
+	b.current[best] -= total
+
+	return b.endpoints[best]
+}
+
+// leastOutstanding picks the endpoint with the fewest in-flight requests
+// relative to its weight
+type leastOutstanding struct {
+	endpoints []*Endpoint
+	next      uint64
+}
+
+func (b *leastOutstanding) Next(string) *Endpoint {
+	// Start from a rotating offset so ties don't always favour the first endpoint
+	offset := int(atomic.AddUint64(&b.next, 1) % uint64(len(b.endpoints)))
+
+	var best *Endpoint
+	for i := range b.endpoints {
+		endpoint := b.endpoints[(offset+i)%len(b.endpoints)]
+		if !endpoint.Healthy() {
+			continue
+		}
+		if best == nil || endpoint.Outstanding()*int64(best.Weight) < best.Outstanding()*int64(endpoint.Weight) {
+			best = endpoint
+		}
+	}
+
+	return best
+}
+
+// virtualNodes is the number of ring positions per unit of endpoint weight
+const virtualNodes = 100
+
+// hashRing maps keys to endpoints with consistent hashing, so a user keeps
+// hitting the same instance and only 1/n of users move when an instance is added
+type hashRing struct {
+	hashes   []uint32
+	owners   map[uint32]*Endpoint
+	fallback roundRobin
+}
+
+func newHashRing(endpoints []*Endpoint) *hashRing {
+	ring := &hashRing{
+		owners:   make(map[uint32]*Endpoint),
+		fallback: roundRobin{endpoints: endpoints},
+	}
+
+	for _, endpoint := range endpoints {
+		for i := 0; i < virtualNodes*endpoint.Weight; i++ {
+			h := hashKey(endpoint.URL.Host + "#" + strconv.Itoa(i))
+			if _, taken := ring.owners[h]; taken {
+				continue
+			}
+			ring.owners[h] = endpoint
+			ring.hashes = append(ring.hashes, h)
+		}
+	}
+	sort.Slice(ring.hashes, func(i, j int) bool { return ring.hashes[i] < ring.hashes[j] })
+
+	return ring
+}
+
+func (r *hashRing) Next(key string) *Endpoint {
+	if key == "" {
+		return r.fallback.Next(key)
+	}
+
+	h := hashKey(key)
+	start := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+
+	// Walk clockwise past unhealthy owners so only their keys are remapped
+	for i := 0; i < len(r.hashes); i++ {
+		owner := r.owners[r.hashes[(start+i)%len(r.hashes)]]
+		if owner.Healthy() {
+			return owner
+		}
+	}
+	return nil
+}
+
+func hashKey(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32()
+}
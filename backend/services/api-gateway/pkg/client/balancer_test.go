@@ -0,0 +1,89 @@
+package client
+
+import (
+	"testing"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEndpoints(t *testing.T, weights ...int) []*Endpoint {
+	var endpoints []*Endpoint
+	for i, weight := range weights {
+		endpoint, err := newEndpoint(config.EndpointConfig{Host: "10.0.0.1", Port: 3000 + i, Weight: weight})
+		require.NoError(t, err)
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+func countPicks(balancer Balancer, n int) map[*Endpoint]int {
+	counts := make(map[*Endpoint]int)
+	for i := 0; i < n; i++ {
+		counts[balancer.Next("")]++
+	}
+	return counts
+}
+
+func TestRoundRobin(t *testing.T) {
+	endpoints := testEndpoints(t, 1, 1, 1)
+	balancer, err := NewBalancer(RoundRobin, endpoints)
+	require.NoError(t, err)
+
+	counts := countPicks(balancer, 30)
+	for _, endpoint := range endpoints {
+		assert.Equal(t, 10, counts[endpoint])
+	}
+}
+
+func TestWeightedRoundRobin(t *testing.T) {
+	endpoints := testEndpoints(t, 3, 1)
+	balancer, err := NewBalancer(WeightedRoundRobin, endpoints)
+	require.NoError(t, err)
+
+	counts := countPicks(balancer, 40)
+	assert.Equal(t, 30, counts[endpoints[0]])
+	assert.Equal(t, 10, counts[endpoints[1]])
+}
+
+func TestLeastOutstanding(t *testing.T) {
+	endpoints := testEndpoints(t, 1, 1, 1)
+	balancer, err := NewBalancer(LeastOutstanding, endpoints)
+	require.NoError(t, err)
+
+	endpoints[0].acquire()
+	endpoints[2].acquire()
+	endpoints[2].acquire()
+
+	assert.Equal(t, endpoints[1], balancer.Next(""))
+}
+
+func TestConsistentHash(t *testing.T) {
+	endpoints := testEndpoints(t, 1, 1, 1)
+	balancer, err := NewBalancer(ConsistentHash, endpoints)
+	require.NoError(t, err)
+
+	first := balancer.Next("user-123")
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first, balancer.Next("user-123"))
+	}
+
+	// Adding an endpoint should only move a fraction of the keys
+	grown, err := NewBalancer(ConsistentHash, append(endpoints, testEndpoints(t, 1, 1, 1, 1)[3]))
+	require.NoError(t, err)
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		key := "user-" + string(rune('a'+i%26)) + string(rune('a'+i/26))
+		if balancer.Next(key).URL.Host != grown.Next(key).URL.Host {
+			moved++
+		}
+	}
+	assert.Less(t, moved, 500)
+}
+
+func TestUnknownBalancer(t *testing.T) {
+	_, err := NewBalancer("random", testEndpoints(t, 1))
+	assert.Error(t, err)
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Circuit breaker states
+const (
+	StateClosed   = "closed"
+	StateHalfOpen = "half_open"
+	StateOpen     = "open"
+)
+
+var (
+	circuitBreakerState = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gateway_circuit_breaker_state",
+			Help: "Circuit breaker state (0 closed, 1 half-open, 2 open)",
+		},
+		[]string{"breaker"},
+	)
+
+	circuitBreakerRejections = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gateway_circuit_breaker_rejections_total",
+			Help: "Requests rejected without calling the upstream because a circuit was open",
+		},
+		[]string{"breaker"},
+	)
+)
+
+var stateValues = map[string]float64{StateClosed: 0, StateHalfOpen: 1, StateOpen: 2}
+
+// CircuitBreaker stops calling an upstream that keeps failing and lets a few
+// probe requests through after a cool-down to see whether it recovered. Every
+// change of state starts a new generation; outcomes of requests let through in
+// an earlier one are ignored, so a slow request cannot act on a state it was
+// not admitted under.
+type CircuitBreaker struct {
+	name   string
+	config config.CircuitBreakerConfig
+	now    func() time.Time
+
+	mu          sync.Mutex
+	state       string
+	generation  uint64
+	openedAt    time.Time
+	windowStart time.Time
+	requests    int
+	failures    int
+	consecutive int
+	probes      int
+	successes   int
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+func NewCircuitBreaker(name string, cfg config.CircuitBreakerConfig) *CircuitBreaker {
+	cb := &CircuitBreaker{
+		name:   name,
+		config: breakerDefaults(cfg),
+		now:    time.Now,
+		state:  StateClosed,
+	}
+	cb.windowStart = cb.now()
+	circuitBreakerState.WithLabelValues(name).Set(stateValues[StateClosed])
+
+	return cb
+}
+
+// breakerDefaults fills in the settings a breaker cannot do without
+func breakerDefaults(cfg config.CircuitBreakerConfig) config.CircuitBreakerConfig {
+	if cfg.HalfOpenRequests <= 0 {
+		cfg.HalfOpenRequests = 1
+	}
+	return cfg
+}
+
+// State returns the current state of the breaker
+func (cb *CircuitBreaker) State() string {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.refresh()
+	return cb.state
+}
+
+// Allow reports whether a request may be sent upstream, and the generation it
+// was let through in, for Record or Cancel. When it may not, it returns how
+// long until the breaker lets probe requests through again.
+func (cb *CircuitBreaker) Allow() (bool, time.Duration, uint64) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.refresh()
+	switch cb.state {
+	case StateOpen:
+		circuitBreakerRejections.WithLabelValues(cb.name).Inc()
+		return false, cb.config.CoolDown - cb.now().Sub(cb.openedAt), cb.generation
+	case StateHalfOpen:
+		if cb.probes >= cb.config.HalfOpenRequests {
+			circuitBreakerRejections.WithLabelValues(cb.name).Inc()
+			return false, time.Second, cb.generation
+		}
+		cb.probes++
+	}
+
+	return true, 0, cb.generation
+}
+
+// Record reports the outcome of a request that Allow let through in the given
+// generation
+func (cb *CircuitBreaker) Record(generation uint64, success bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if generation != cb.generation {
+		return
+	}
+
+	if cb.state == StateHalfOpen {
+		if !success {
+			cb.setState(StateOpen)
+			return
+		}
+		cb.successes++
+		if cb.successes >= cb.config.HalfOpenRequests {
+			cb.setState(StateClosed)
+		}
+		return
+	}
+
+	if cb.config.Window > 0 && cb.now().Sub(cb.windowStart) > cb.config.Window {
+		cb.resetWindow()
+	}
+	cb.requests++
+	if success {
+		cb.consecutive = 0
+	} else {
+		cb.failures++
+		cb.consecutive++
+	}
+
+	if cb.tripped() {
+		cb.setState(StateOpen)
+	}
+}
+
+// Cancel gives back what Allow took for a request that ended without an
+// outcome, such as one the client abandoned
+func (cb *CircuitBreaker) Cancel(generation uint64) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if generation == cb.generation && cb.state == StateHalfOpen && cb.probes > 0 {
+		cb.probes--
+	}
+}
+
+// tripped reports whether the current window crossed a failure threshold
+func (cb *CircuitBreaker) tripped() bool {
+	if cb.config.ConsecutiveFailures > 0 && cb.consecutive >= cb.config.ConsecutiveFailures {
+		return true
+	}
+	if cb.config.ErrorRateThreshold > 0 && cb.requests >= cb.config.MinRequests {
+		return float64(cb.failures)/float64(cb.requests) >= cb.config.ErrorRateThreshold
+	}
+	return false
+}
+
+// refresh moves an open breaker to half-open once the cool-down has passed
+func (cb *CircuitBreaker) refresh() {
+	if cb.state == StateOpen && cb.now().Sub(cb.openedAt) >= cb.config.CoolDown {
+		cb.setState(StateHalfOpen)
+	}
+}
+
+func (cb *CircuitBreaker) setState(state string) {
+	cb.state = state
+	cb.generation++
+	cb.probes = 0
+	cb.successes = 0
+	if state == StateOpen {
+		cb.openedAt = cb.now()
+	}
+	cb.resetWindow()
+	circuitBreakerState.WithLabelValues(cb.name).Set(stateValues[state])
+}
+
+func (cb *CircuitBreaker) resetWindow() {
+	cb.windowStart = cb.now()
+	cb.requests = 0
+	cb.failures = 0
+	cb.consecutive = 0
+}
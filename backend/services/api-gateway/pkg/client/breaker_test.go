@@ -0,0 +1,125 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBreaker(cfg config.CircuitBreakerConfig) (*CircuitBreaker, *time.Time) {
+	now := time.Unix(1700000000, 0)
+	cb := NewCircuitBreaker("test", cfg)
+	cb.now = func() time.Time { return now }
+	cb.windowStart = now
+	return cb, &now
+}
+
+// record reports the outcome of a request at once, if the breaker lets it
+// through
+func record(cb *CircuitBreaker, success bool) {
+	if allowed, _, generation := cb.Allow(); allowed {
+		cb.Record(generation, success)
+	}
+}
+
+func TestCircuitBreakerConsecutiveFailures(t *testing.T) {
+	cb, now := newTestBreaker(config.CircuitBreakerConfig{
+		ConsecutiveFailures: 3,
+		CoolDown:            10 * time.Second,
+	})
+
+	record(cb, false)
+	record(cb, false)
+	record(cb, true)
+	record(cb, false)
+	record(cb, false)
+	assert.Equal(t, StateClosed, cb.State(), "a success resets the streak")
+
+	record(cb, false)
+	assert.Equal(t, StateOpen, cb.State())
+
+	allowed, wait, _ := cb.Allow()
+	assert.False(t, allowed)
+	assert.Equal(t, 10*time.Second, wait)
+
+	*now = now.Add(10 * time.Second)
+	assert.Equal(t, StateHalfOpen, cb.State())
+
+	allowed, _, probe := cb.Allow()
+	assert.True(t, allowed, "one probe is let through")
+	allowed, _, _ = cb.Allow()
+	assert.False(t, allowed, "further requests wait for the probe")
+
+	cb.Cancel(probe)
+	allowed, _, probe = cb.Allow()
+	assert.True(t, allowed, "a cancelled probe frees its slot")
+
+	cb.Record(probe, true)
+	assert.Equal(t, StateClosed, cb.State())
+}
+
+func TestCircuitBreakerErrorRate(t *testing.T) {
+	cb, _ := newTestBreaker(config.CircuitBreakerConfig{
+		ErrorRateThreshold: 0.5,
+		MinRequests:        4,
+		Window:             time.Minute,
+		CoolDown:           time.Second,
+	})
+
+	record(cb, false)
+	record(cb, true)
+	record(cb, false)
+	assert.Equal(t, StateClosed, cb.State(), "below the minimum request count")
+
+	record(cb, true)
+	assert.Equal(t, StateOpen, cb.State())
+}
+
+func TestCircuitBreakerHalfOpenFailure(t *testing.T) {
+	cb, now := newTestBreaker(config.CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		CoolDown:            time.Second,
+	})
+
+	record(cb, false)
+	*now = now.Add(time.Second)
+
+	allowed, _, probe := cb.Allow()
+	assert.True(t, allowed)
+	cb.Record(probe, false)
+	assert.Equal(t, StateOpen, cb.State())
+}
+
+func TestCircuitBreakerOverlappingRequests(t *testing.T) {
+	cb, now := newTestBreaker(config.CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		CoolDown:            10 * time.Second,
+	})
+
+	// Two slow requests are let through while closed, then a fast one trips it
+	_, _, slowFailure := cb.Allow()
+	_, _, slowSuccess := cb.Allow()
+	record(cb, false)
+	assert.Equal(t, StateOpen, cb.State())
+
+	// A slow failure arriving once open does not restart the cool-down
+	*now = now.Add(5 * time.Second)
+	cb.Record(slowFailure, false)
+	*now = now.Add(5 * time.Second)
+	assert.Equal(t, StateHalfOpen, cb.State())
+
+	// Nor does a slow success count as the probe's
+	allowed, _, probe := cb.Allow()
+	assert.True(t, allowed)
+	cb.Record(slowSuccess, true)
+	cb.Cancel(slowSuccess)
+	assert.Equal(t, StateHalfOpen, cb.State())
+	allowed, _, _ = cb.Allow()
+	assert.False(t, allowed, "the probe still holds its slot")
+
+	cb.Record(probe, true)
+	assert.Equal(t, StateClosed, cb.State())
+}
@@ -0,0 +1,202 @@
+package client
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Route priorities, in the order they are shed
+const (
+	PriorityLow      = "low"
+	PriorityNormal   = "normal"
+	PriorityCritical = "critical"
+)
+
+// Tuning of the limit's adaptation
+const (
+	// baselineWindows is how many windows the long-run latency averages over
+	baselineWindows = 20
+	// limitSmoothing is how far each window moves the limit to its new value
+	limitSmoothing = 0.2
+	// minGradient bounds how much one window can shrink the limit
+	minGradient = 0.5
+)
+
+// shedRetryAfter is how long shed clients are asked to wait
+const shedRetryAfter = time.Second
+
+var (
+	concurrencyLimit = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gateway_concurrency_limit",
+			Help: "Requests that may be in flight to a service, as currently adapted",
+		},
+		[]string{"service"},
+	)
+
+	concurrencyInFlight = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gateway_concurrency_in_flight",
+			Help: "Requests in flight to a service",
+		},
+		[]string{"service"},
+	)
+
+	concurrencyShed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gateway_concurrency_shed_total",
+			Help: "Requests shed because a service was at its concurrency limit, by route priority",
+		},
+		[]string{"service", "priority"},
+	)
+)
+
+// ConcurrencyLimiter caps the requests in flight to a service at a limit that
+// adapts to the service's latency. Every window the limit is scaled by the
+// gradient between the long-run average latency, allowing for the tolerance,
+// and the window's average, then given headroom of its square root to probe
+// for more capacity. Windows with 5xx responses take the steepest gradient,
+// without headroom. Each window only moves the limit part of the way.
+type ConcurrencyLimiter struct {
+	name   string
+	config config.ConcurrencyConfig
+	now    func() time.Time
+
+	mu          sync.Mutex
+	limit       float64
+	inFlight    int
+	baseline    time.Duration
+	windowStart time.Time
+	samples     int
+	latency     time.Duration
+	failed      bool
+	peak        int
+}
+
+// NewConcurrencyLimiter creates a limiter starting at the initial limit
+func NewConcurrencyLimiter(name string, cfg config.ConcurrencyConfig) *ConcurrencyLimiter {
+	l := &ConcurrencyLimiter{
+		name:   name,
+		config: cfg,
+		now:    time.Now,
+		limit:  float64(cfg.InitialLimit),
+	}
+	l.windowStart = l.now()
+	concurrencyLimit.WithLabelValues(name).Set(l.limit)
+	concurrencyInFlight.WithLabelValues(name).Set(0)
+
+	return l
+}
+
+// Limit returns the current limit
+func (l *ConcurrencyLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return int(l.limit)
+}
+
+// Acquire takes a slot for a request of the given priority, reporting false
+// when the request should be shed. Low priority requests may only fill part
+// of the limit; critical ones are only held to the maximum.
+func (l *ConcurrencyLimiter) Acquire(priority string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := l.limit
+	switch priority {
+	case PriorityLow:
+		limit *= l.config.LowPriorityShare
+	case PriorityCritical:
+		limit = float64(l.config.MaxLimit)
+	default:
+		priority = PriorityNormal
+	}
+	if float64(l.inFlight) >= limit {
+		concurrencyShed.WithLabelValues(l.name, priority).Inc()
+		return false
+	}
+
+	l.inFlight++
+	l.peak = max(l.peak, l.inFlight)
+	concurrencyInFlight.WithLabelValues(l.name).Set(float64(l.inFlight))
+	return true
+}
+
+// Release gives back a slot, sampling how long the request took and whether
+// the service failed it
+func (l *ConcurrencyLimiter) Release(latency time.Duration, failed bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.release()
+	if failed {
+		l.failed = true
+	} else {
+		l.samples++
+		l.latency += latency
+	}
+
+	if l.now().Sub(l.windowStart) >= l.config.Window {
+		l.update()
+	}
+}
+
+// Cancel gives back a slot without a sample, for requests that never got an
+// answer from the service
+func (l *ConcurrencyLimiter) Cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.release()
+}
+
+func (l *ConcurrencyLimiter) release() {
+	l.inFlight--
+	concurrencyInFlight.WithLabelValues(l.name).Set(float64(l.inFlight))
+}
+
+// update adapts the limit to the window that just ended and starts the next
+func (l *ConcurrencyLimiter) update() {
+	defer l.resetWindow()
+
+	var limit float64
+	switch {
+	case l.failed:
+		limit = l.limit * minGradient
+	case l.samples == 0:
+		return
+	default:
+		latency := l.latency / time.Duration(l.samples)
+		if l.baseline == 0 {
+			l.baseline = latency
+		}
+		gradient := l.config.Tolerance * float64(l.baseline) / float64(latency)
+		gradient = math.Max(minGradient, math.Min(1, gradient))
+		l.baseline += (latency - l.baseline) / baselineWindows
+
+		limit = l.limit*gradient + math.Sqrt(l.limit)
+	}
+	if float64(l.peak) < l.limit/2 && limit > l.limit {
+		// A mostly idle limit says nothing about capacity
+		limit = l.limit
+	}
+	limit = l.limit + (limit-l.limit)*limitSmoothing
+
+	l.limit = math.Max(float64(l.config.MinLimit), math.Min(float64(l.config.MaxLimit), limit))
+	concurrencyLimit.WithLabelValues(l.name).Set(l.limit)
+}
+
+func (l *ConcurrencyLimiter) resetWindow() {
+	l.windowStart = l.now()
+	l.samples = 0
+	l.latency = 0
+	l.failed = false
+	l.peak = l.inFlight
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestLimiter(initial int) (*ConcurrencyLimiter, *time.Time) {
+	now := time.Unix(1700000000, 0)
+	l := NewConcurrencyLimiter("test", config.ConcurrencyConfig{
+		InitialLimit:     initial,
+		MinLimit:         5,
+		MaxLimit:         100,
+		Tolerance:        2,
+		Window:           time.Second,
+		LowPriorityShare: 0.5,
+	})
+	l.now = func() time.Time { return now }
+	l.windowStart = now
+	return l, &now
+}
+
+// runWindow keeps inFlight requests in flight and completes them with the
+// given latency, then ends the window
+func runWindow(l *ConcurrencyLimiter, now *time.Time, inFlight int, latency time.Duration, failed bool) {
+	for i := 0; i < inFlight; i++ {
+		l.Acquire(PriorityCritical)
+	}
+	for i := 0; i < inFlight-1; i++ {
+		l.Release(latency, failed)
+	}
+	*now = now.Add(time.Second)
+	l.Release(latency, failed)
+}
+
+func TestConcurrencyLimiterPriorities(t *testing.T) {
+	l, _ := newTestLimiter(10)
+
+	for i := 0; i < 5; i++ {
+		assert.True(t, l.Acquire(PriorityLow))
+	}
+	assert.False(t, l.Acquire(PriorityLow), "low priority fills half the limit")
+
+	for i := 0; i < 5; i++ {
+		assert.True(t, l.Acquire(PriorityNormal))
+	}
+	assert.False(t, l.Acquire(PriorityNormal))
+	assert.False(t, l.Acquire(""))
+	assert.True(t, l.Acquire(PriorityCritical), "critical requests go past the limit")
+
+	l.Cancel()
+	l.Cancel()
+	assert.True(t, l.Acquire(PriorityNormal))
+}
+
+func TestConcurrencyLimiterAdapts(t *testing.T) {
+	l, now := newTestLimiter(20)
+
+	// Steady latency under load probes upwards
+	for i := 0; i < 5; i++ {
+		runWindow(l, now, 20, 100*time.Millisecond, false)
+	}
+	assert.Greater(t, l.Limit(), 20)
+
+	// An idle service does not earn a higher limit
+	grown := l.Limit()
+	runWindow(l, now, 2, 100*time.Millisecond, false)
+	assert.Equal(t, grown, l.Limit())
+
+	// Latency within tolerance of the baseline is fine, beyond it shrinks
+	runWindow(l, now, 20, 190*time.Millisecond, false)
+	assert.GreaterOrEqual(t, l.Limit(), grown)
+	for i := 0; i < 5; i++ {
+		runWindow(l, now, 20, 2*time.Second, false)
+	}
+	assert.Less(t, l.Limit(), 20)
+
+	// Failures shrink it down to the minimum
+	for i := 0; i < 30; i++ {
+		runWindow(l, now, 5, 10*time.Millisecond, true)
+	}
+	assert.Equal(t, 5, l.Limit())
+}
+
+func TestProxyRequestShedsOverload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer upstream.Close()
+
+	service := testServiceConfig(t, upstream)
+	service.Concurrency = config.ConcurrencyConfig{
+		Enabled:          true,
+		InitialLimit:     2,
+		MinLimit:         1,
+		MaxLimit:         10,
+		Tolerance:        2,
+		Window:           time.Second,
+		LowPriorityShare: 0.5,
+	}
+	cfg := &config.Config{}
+	cfg.Services = config.ServicesConfig{"report-service": service}
+	manager, err := NewManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	router := gin.New()
+	for _, priority := range []string{PriorityLow, PriorityNormal} {
+		client := manager.GetClient("report-service").WithPriority(priority)
+		router.GET("/"+priority, func(c *gin.Context) {
+			client.ProxyRequest(c, "/")
+		})
+	}
+
+	gateway := httptest.NewServer(router)
+	defer gateway.Close()
+	defer close(release)
+
+	// One request in flight leaves room for normal requests but not low ones
+	go http.Get(gateway.URL + "/normal")
+	limiter := manager.upstreams["report-service"].limiter
+	require.Eventually(t, func() bool {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return limiter.inFlight == 1
+	}, time.Second, 10*time.Millisecond)
+
+	resp, err := http.Get(gateway.URL + "/low")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+	assert.Contains(t, string(body), "SERVICE_OVERLOADED")
+}
@@ -0,0 +1,305 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"baribhara/api-gateway/internal/config"
+	"baribhara/api-gateway/internal/middleware"
+	// Register the shared proto definitions so routes can name their methods
+	_ "baribhara/api-gateway/pkg/pb/authpb"
+	_ "baribhara/api-gateway/pkg/pb/propertypb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// forwardedMetadata are the request headers passed to gRPC upstreams, besides
+// the X-User-* identity headers
+var forwardedMetadata = []string{"Authorization", "X-Request-Id"}
+
+// grpcPool spreads calls to a service over a few long-lived connections, one
+// or more per endpoint. It implements grpc.ClientConnInterface.
+type grpcPool struct {
+	conns []*grpc.ClientConn
+	next  uint32
+}
+
+// newGRPCPool creates a client for the gRPC port of every endpoint of a
+// service. Connections are established in the background on first use.
+func newGRPCPool(cfg config.ServiceConfig, tlsConfig *tls.Config) (*grpcPool, error) {
+	if cfg.GRPCPort == 0 {
+		return nil, fmt.Errorf("no grpc_port configured")
+	}
+
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	options := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if cfg.Transport.KeepAlive > 0 {
+		options = append(options, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time: cfg.Transport.KeepAlive,
+		}))
+	}
+
+	endpoints := cfg.ResolvedEndpoints()
+	size := cfg.GRPCPoolSize
+	if size < len(endpoints) {
+		size = len(endpoints)
+	}
+
+	pool := &grpcPool{}
+	for i := 0; i < size; i++ {
+		target := net.JoinHostPort(endpoints[i%len(endpoints)].Host, strconv.Itoa(cfg.GRPCPort))
+		conn, err := grpc.NewClient(target, options...)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		pool.conns = append(pool.conns, conn)
+	}
+
+	return pool, nil
+}
+
+func (p *grpcPool) pick() *grpc.ClientConn {
+	return p.conns[atomic.AddUint32(&p.next, 1)%uint32(len(p.conns))]
+}
+
+// Invoke implements grpc.ClientConnInterface
+func (p *grpcPool) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	return p.pick().Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream implements grpc.ClientConnInterface
+func (p *grpcPool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return p.pick().NewStream(ctx, desc, method, opts...)
+}
+
+// Close closes every connection in the pool
+func (p *grpcPool) Close() {
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+}
+
+// GRPCMethod transcodes HTTP/JSON requests to a unary gRPC method
+type GRPCMethod struct {
+	conn    grpc.ClientConnInterface
+	name    string
+	input   protoreflect.MessageType
+	output  protoreflect.MessageType
+	params  map[string]string
+	maxBody int64
+	logger  *zap.Logger
+}
+
+// NewGRPCMethod resolves a method such as "auth.AuthService/Login" against the
+// registered proto definitions. params maps path parameters to request fields.
+func (m *Manager) NewGRPCMethod(service, method string, params map[string]string) (*GRPCMethod, error) {
+	serviceName, methodName, ok := strings.Cut(method, "/")
+	if !ok {
+		return nil, fmt.Errorf("grpc method %q must look like package.Service/Method", method)
+	}
+	descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, fmt.Errorf("unknown grpc service %q", serviceName)
+	}
+	serviceDescriptor, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a grpc service", serviceName)
+	}
+	methodDescriptor := serviceDescriptor.Methods().ByName(protoreflect.Name(methodName))
+	if methodDescriptor == nil {
+		return nil, fmt.Errorf("unknown grpc method %q", method)
+	}
+	if methodDescriptor.IsStreamingClient() || methodDescriptor.IsStreamingServer() {
+		return nil, fmt.Errorf("grpc method %q is streaming; only unary methods can be routed", method)
+	}
+
+	input, err := protoregistry.GlobalTypes.FindMessageByName(methodDescriptor.Input().FullName())
+	if err != nil {
+		return nil, err
+	}
+	output, err := protoregistry.GlobalTypes.FindMessageByName(methodDescriptor.Output().FullName())
+	if err != nil {
+		return nil, err
+	}
+
+	fields := input.Descriptor().Fields()
+	for param, field := range params {
+		if fields.ByName(protoreflect.Name(field)) == nil && fields.ByJSONName(field) == nil {
+			return nil, fmt.Errorf("path parameter %q: %s has no field %q", param, input.Descriptor().FullName(), field)
+		}
+	}
+
+	conn, err := m.GRPCConn(service)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GRPCMethod{
+		conn:    conn,
+		name:    "/" + method,
+		input:   input,
+		output:  output,
+		params:  params,
+		maxBody: m.config.Services[service].GRPCMaxBody,
+		logger:  m.logger,
+	}, nil
+}
+
+// GRPCConn returns the pooled gRPC connections of a service, dialing them on
+// first use
+func (m *Manager) GRPCConn(service string) (grpc.ClientConnInterface, error) {
+	m.grpcMu.Lock()
+	defer m.grpcMu.Unlock()
+
+	if pool, ok := m.grpcPools[service]; ok {
+		return pool, nil
+	}
+
+	upstream, ok := m.upstreams[service]
+	if !ok {
+		return nil, fmt.Errorf("unknown service %q", service)
+	}
+	pool, err := newGRPCPool(m.config.Services[service], upstream.tls)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", service, err)
+	}
+	m.grpcPools[service] = pool
+
+	return pool, nil
+}
+
+// ProxyGRPC calls a gRPC method with a request transcoded from the HTTP request
+func (sc *ServiceClient) ProxyGRPC(c *gin.Context, method *GRPCMethod) {
+	sc.guardRequest(c, func() bool {
+		return method.invoke(c)
+	})
+}
+
+// invoke calls the method for a request, reporting whether the service failed it
+func (m *GRPCMethod) invoke(c *gin.Context) bool {
+	req := m.input.New().Interface()
+
+	// Body first, then query parameters, then path parameters
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, m.maxBody))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large", "code": "REQUEST_TOO_LARGE"})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return false
+	}
+	if len(body) > 0 {
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return false
+		}
+	}
+	if err := runtime.PopulateQueryParameters(req, c.Request.URL.Query(), utilities.NewDoubleArray(nil)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter"})
+		return false
+	}
+	for _, param := range c.Params {
+		field, ok := m.params[param.Key]
+		if !ok {
+			continue
+		}
+		if err := runtime.PopulateFieldFromPath(req, field, param.Value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid path parameter " + param.Key})
+			return false
+		}
+	}
+
+	md := outgoingMetadata(c.Request.Header)
+	md.Set("x-forwarded-for", c.ClientIP())
+	ctx := metadata.NewOutgoingContext(c.Request.Context(), md)
+
+	resp := m.output.New().Interface()
+	if err := m.conn.Invoke(ctx, m.name, req, resp); err != nil {
+		m.writeError(c, err)
+		return upstreamFault(err)
+	}
+
+	out, err := (protojson.MarshalOptions{EmitUnpopulated: true}).Marshal(resp)
+	if err != nil {
+		m.writeError(c, err)
+		return false
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", out)
+	return false
+}
+
+// outgoingMetadata picks the request headers forwarded to gRPC upstreams
+func outgoingMetadata(header http.Header) metadata.MD {
+	md := metadata.MD{}
+	for _, name := range forwardedMetadata {
+		if value := header.Get(name); value != "" {
+			md.Set(name, value)
+		}
+	}
+	for name, values := range header {
+		if strings.HasPrefix(name, middleware.IdentityHeaderPrefix) {
+			md.Set(name, values...)
+		}
+	}
+	return md
+}
+
+// writeError maps a gRPC status to an HTTP response. Messages of client errors
+// are passed through; server errors get the same generic bodies as HTTP routes.
+func (m *GRPCMethod) writeError(c *gin.Context, err error) {
+	st := status.Convert(err)
+	httpStatus := runtime.HTTPStatusFromCode(st.Code())
+
+	if httpStatus < http.StatusInternalServerError {
+		c.JSON(httpStatus, gin.H{"error": st.Message(), "code": statusCode(st.Code())})
+		return
+	}
+
+	m.logger.Error("gRPC upstream error", zap.String("method", m.name), zap.Error(err))
+	switch st.Code() {
+	case codes.DeadlineExceeded:
+		c.JSON(httpStatus, gin.H{"error": "Upstream request timed out", "code": "UPSTREAM_TIMEOUT"})
+	default:
+		c.JSON(httpStatus, gin.H{"error": "Service unavailable", "code": statusCode(st.Code())})
+	}
+}
+
+// statusCode spells a gRPC code the way the gateway spells error codes, e.g.
+// NotFound -> NOT_FOUND
+func statusCode(code codes.Code) string {
+	var b strings.Builder
+	for i, r := range code.String() {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"baribhara/api-gateway/internal/config"
+	"baribhara/api-gateway/pkg/pb/propertypb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type testPropertyServer struct {
+	propertypb.UnimplementedPropertyServiceServer
+}
+
+func (testPropertyServer) GetProperty(ctx context.Context, req *propertypb.GetPropertyRequest) (*propertypb.GetPropertyResponse, error) {
+	if req.PropertyId == "missing" {
+		return nil, status.Error(codes.NotFound, "property not found")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return &propertypb.GetPropertyResponse{
+		Success:  true,
+		Property: &propertypb.Property{Id: req.PropertyId, Description: strings.Join(md.Get("authorization"), "")},
+	}, nil
+}
+
+func (testPropertyServer) SearchProperties(ctx context.Context, req *propertypb.SearchPropertiesRequest) (*propertypb.SearchPropertiesResponse, error) {
+	return &propertypb.SearchPropertiesResponse{
+		Success: true,
+		Total:   req.MinBedrooms,
+		Properties: []*propertypb.Property{
+			{Address: &propertypb.PropertyAddress{City: req.City}, Name: req.Query},
+		},
+	}, nil
+}
+
+func TestProxyGRPC(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	propertypb.RegisterPropertyServiceServer(server, testPropertyServer{})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	cfg := &config.Config{}
+	cfg.Services = config.ServicesConfig{
+		"property-service": {
+			Host:         "127.0.0.1",
+			Port:         1,
+			GRPCPort:     listener.Addr().(*net.TCPAddr).Port,
+			GRPCPoolSize: 2,
+			GRPCMaxBody:  64,
+		},
+	}
+	manager, err := NewManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+	defer manager.Close()
+
+	getProperty, err := manager.NewGRPCMethod("property-service", "property.PropertyService/GetProperty", map[string]string{"id": "propertyId"})
+	require.NoError(t, err)
+	searchProperties, err := manager.NewGRPCMethod("property-service", "property.PropertyService/SearchProperties", nil)
+	require.NoError(t, err)
+
+	_, err = manager.NewGRPCMethod("property-service", "property.PropertyService/Missing", nil)
+	assert.Error(t, err)
+	_, err = manager.NewGRPCMethod("property-service", "property.PropertyService/GetProperty", map[string]string{"id": "nope"})
+	assert.Error(t, err)
+
+	router := gin.New()
+	router.GET("/properties/:id", func(c *gin.Context) {
+		manager.GetClient("property-service").ProxyGRPC(c, getProperty)
+	})
+	router.POST("/properties/search", func(c *gin.Context) {
+		manager.GetClient("property-service").ProxyGRPC(c, searchProperties)
+	})
+	gateway := httptest.NewServer(router)
+	defer gateway.Close()
+
+	call := func(req *http.Request) (int, map[string]interface{}) {
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		var decoded map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &decoded), string(body))
+		return resp.StatusCode, decoded
+	}
+
+	// Path parameters fill mapped fields; metadata carries Authorization
+	req, _ := http.NewRequest(http.MethodGet, gateway.URL+"/properties/p-1", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	code, body := call(req)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, body["success"])
+	assert.Equal(t, "p-1", body["property"].(map[string]interface{})["id"])
+	assert.Equal(t, "Bearer token", body["property"].(map[string]interface{})["description"])
+
+	// gRPC status codes map to HTTP status codes
+	req, _ = http.NewRequest(http.MethodGet, gateway.URL+"/properties/missing", nil)
+	code, body = call(req)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, "NOT_FOUND", body["code"])
+	assert.Equal(t, "property not found", body["error"])
+
+	// The JSON body and query parameters are both transcoded
+	req, _ = http.NewRequest(http.MethodPost, gateway.URL+"/properties/search?city=Dhaka", strings.NewReader(`{"query": "flat", "minBedrooms": 2}`))
+	code, body = call(req)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(2), body["total"])
+	property := body["properties"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Dhaka", property["address"].(map[string]interface{})["city"])
+	assert.Equal(t, "flat", property["name"])
+
+	// A malformed body is rejected before any call is made
+	req, _ = http.NewRequest(http.MethodPost, gateway.URL+"/properties/search", strings.NewReader(`{"minBedrooms": "many"}`))
+	code, _ = call(req)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// As is one past the size limit, without being read in full
+	req, _ = http.NewRequest(http.MethodPost, gateway.URL+"/properties/search", strings.NewReader(`{"query": "`+strings.Repeat("x", 1<<20)+`"}`))
+	code, body = call(req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+	assert.Equal(t, "REQUEST_TOO_LARGE", body["code"])
+}
+
+func TestStatusCode(t *testing.T) {
+	assert.Equal(t, "NOT_FOUND", statusCode(codes.NotFound))
+	assert.Equal(t, "DEADLINE_EXCEEDED", statusCode(codes.DeadlineExceeded))
+	assert.Equal(t, "UNAVAILABLE", statusCode(codes.Unavailable))
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"baribhara/api-gateway/internal/config"
+)
+
+// hopByHopHeaders apply to a single connection and must not be forwarded
+var hopByHopHeaders = []string{
+	"Connection",
+	"Proxy-Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// HeaderPolicy rewrites the headers of proxied requests and responses
+type HeaderPolicy struct {
+	request        []headerRules
+	response       []headerRules
+	trustedProxies []*net.IPNet
+}
+
+// headerRules is a HeaderRulesConfig with canonicalized header names
+type headerRules struct {
+	remove   []string
+	prefixes []string
+	rename   map[string]string
+	set      map[string]string
+	append   map[string]string
+}
+
+// NewHeaderPolicy builds the global header policy
+func NewHeaderPolicy(cfg config.HeaderPolicyConfig, trustedProxies []string) (*HeaderPolicy, error) {
+	policy := &HeaderPolicy{
+		request:  []headerRules{newHeaderRules(cfg.Request)},
+		response: []headerRules{newHeaderRules(cfg.Response)},
+	}
+
+	networks, err := config.ParseCIDRs(trustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("trusted proxy: %w", err)
+	}
+	policy.trustedProxies = networks
+
+	return policy, nil
+}
+
+// With returns a policy that applies route rules after the receiver's rules
+func (p *HeaderPolicy) With(cfg *config.HeaderPolicyConfig) *HeaderPolicy {
+	if cfg == nil {
+		return p
+	}
+
+	return &HeaderPolicy{
+		request:        append(append([]headerRules{}, p.request...), newHeaderRules(cfg.Request)),
+		response:       append(append([]headerRules{}, p.response...), newHeaderRules(cfg.Response)),
+		trustedProxies: p.trustedProxies,
+	}
+}
+
+func newHeaderRules(cfg config.HeaderRulesConfig) headerRules {
+	rules := headerRules{
+		rename: make(map[string]string),
+		set:    make(map[string]string),
+		append: make(map[string]string),
+	}
+
+	for _, name := range cfg.Remove {
+		if strings.HasSuffix(name, "*") {
+			rules.prefixes = append(rules.prefixes, http.CanonicalHeaderKey(strings.TrimSuffix(name, "*")))
+			continue
+		}
+		rules.remove = append(rules.remove, http.CanonicalHeaderKey(name))
+	}
+	for from, to := range cfg.Rename {
+		rules.rename[http.CanonicalHeaderKey(from)] = http.CanonicalHeaderKey(to)
+	}
+	for name, value := range cfg.Set {
+		rules.set[http.CanonicalHeaderKey(name)] = value
+	}
+	for name, value := range cfg.Append {
+		rules.append[http.CanonicalHeaderKey(name)] = value
+	}
+
+	return rules
+}
+
+// ApplyRequest prepares the headers of an outgoing upstream request. Incoming
+// X-Forwarded-* headers are only kept when the client connected through a
+// trusted proxy; X-Forwarded-For is then extended with the peer address by
+// the reverse proxy.
+func (p *HeaderPolicy) ApplyRequest(out *http.Request) {
+	// Protocol upgrades (websockets) keep the two headers that request them
+	upgrade := ""
+	if headerHasToken(out.Header, "Connection", "upgrade") {
+		upgrade = out.Header.Get("Upgrade")
+	}
+	stripHopByHop(out.Header)
+	if upgrade != "" {
+		out.Header.Set("Connection", "Upgrade")
+		out.Header.Set("Upgrade", upgrade)
+	}
+
+	if !p.trustedPeer(out.RemoteAddr) {
+		out.Header.Del("X-Forwarded-For")
+		out.Header.Del("X-Forwarded-Proto")
+		out.Header.Del("X-Forwarded-Host")
+		out.Header.Del("Forwarded")
+		out.Header.Del("X-Real-Ip")
+	}
+	if out.Header.Get("X-Forwarded-Proto") == "" {
+		proto := "http"
+		if out.TLS != nil {
+			proto = "https"
+		}
+		out.Header.Set("X-Forwarded-Proto", proto)
+	}
+	if out.Header.Get("X-Forwarded-Host") == "" {
+		out.Header.Set("X-Forwarded-Host", out.Host)
+	}
+
+	for _, rules := range p.request {
+		rules.apply(out.Header)
+	}
+}
+
+// ApplyResponse rewrites the headers of an upstream response
+func (p *HeaderPolicy) ApplyResponse(resp *http.Response) {
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		stripHopByHop(resp.Header)
+	}
+
+	for _, rules := range p.response {
+		rules.apply(resp.Header)
+	}
+}
+
+func (p *HeaderPolicy) trustedPeer(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range p.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r headerRules) apply(header http.Header) {
+	for _, name := range r.remove {
+		header.Del(name)
+	}
+	if len(r.prefixes) > 0 {
+		for name := range header {
+			for _, prefix := range r.prefixes {
+				if strings.HasPrefix(name, prefix) {
+					delete(header, name)
+					break
+				}
+			}
+		}
+	}
+	for from, to := range r.rename {
+		if values, ok := header[from]; ok {
+			delete(header, from)
+			header[to] = append(header[to], values...)
+		}
+	}
+	for name, value := range r.set {
+		header.Set(name, value)
+	}
+	for name, value := range r.append {
+		header.Add(name, value)
+	}
+}
+
+// stripHopByHop removes hop-by-hop headers, including any named in Connection
+func stripHopByHop(header http.Header) {
+	for _, value := range header.Values("Connection") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				header.Del(name)
+			}
+		}
+	}
+	for _, name := range hopByHopHeaders {
+		header.Del(name)
+	}
+}
+
+// headerHasToken reports whether a comma-separated header contains token
+func headerHasToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderPolicyRequest(t *testing.T) {
+	policy, err := NewHeaderPolicy(config.HeaderPolicyConfig{
+		Request: config.HeaderRulesConfig{
+			Remove: []string{"X-Internal-*", "Cookie"},
+			Rename: map[string]string{"x-client-version": "X-App-Version"},
+			Set:    map[string]string{"x-gateway": "baribhara"},
+			Append: map[string]string{"via": "1.1 api-gateway"},
+		},
+	}, []string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		remoteAddr        string
+		expectedForwarded string
+		expectedProto     string
+	}{
+		{
+			name:              "Untrusted peer loses forged forwarding headers",
+			remoteAddr:        "203.0.113.9:5000",
+			expectedForwarded: "",
+			expectedProto:     "http",
+		},
+		{
+			name:              "Trusted proxy keeps forwarding headers",
+			remoteAddr:        "10.1.2.3:5000",
+			expectedForwarded: "198.51.100.7",
+			expectedProto:     "https",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://gateway.example/api/v1/tenants", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", "198.51.100.7")
+			req.Header.Set("X-Forwarded-Proto", "https")
+			req.Header.Set("X-Internal-User", "admin")
+			req.Header.Set("Cookie", "session=1")
+			req.Header.Set("X-Client-Version", "2.4.0")
+			req.Header.Set("Via", "1.1 cdn")
+			req.Header.Set("Connection", "keep-alive, X-Secret")
+			req.Header.Set("X-Secret", "hop")
+
+			policy.ApplyRequest(req)
+
+			assert.Equal(t, tt.expectedForwarded, req.Header.Get("X-Forwarded-For"))
+			assert.Equal(t, tt.expectedProto, req.Header.Get("X-Forwarded-Proto"))
+			assert.Equal(t, "gateway.example", req.Header.Get("X-Forwarded-Host"))
+			assert.Empty(t, req.Header.Get("X-Internal-User"))
+			assert.Empty(t, req.Header.Get("Cookie"))
+			assert.Empty(t, req.Header.Get("Connection"))
+			assert.Empty(t, req.Header.Get("X-Secret"))
+			assert.Empty(t, req.Header.Get("X-Client-Version"))
+			assert.Equal(t, "2.4.0", req.Header.Get("X-App-Version"))
+			assert.Equal(t, "baribhara", req.Header.Get("X-Gateway"))
+			assert.Equal(t, []string{"1.1 cdn", "1.1 api-gateway"}, req.Header.Values("Via"))
+		})
+	}
+}
+
+func TestHeaderPolicyKeepsUpgrade(t *testing.T) {
+	policy, err := NewHeaderPolicy(config.HeaderPolicyConfig{}, nil)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/ws", nil)
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+
+	policy.ApplyRequest(req)
+
+	assert.Equal(t, "Upgrade", req.Header.Get("Connection"))
+	assert.Equal(t, "websocket", req.Header.Get("Upgrade"))
+}
+
+func TestHeaderPolicyResponse(t *testing.T) {
+	policy, err := NewHeaderPolicy(config.HeaderPolicyConfig{
+		Response: config.HeaderRulesConfig{Remove: []string{"X-Powered-By"}},
+	}, nil)
+	require.NoError(t, err)
+
+	route := policy.With(&config.HeaderPolicyConfig{
+		Response: config.HeaderRulesConfig{Set: map[string]string{"cache-control": "no-store"}},
+	})
+
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set("X-Powered-By", "Express")
+	resp.Header.Set("Keep-Alive", "timeout=5")
+
+	route.ApplyResponse(resp)
+
+	assert.Empty(t, resp.Header.Get("X-Powered-By"))
+	assert.Empty(t, resp.Header.Get("Keep-Alive"))
+	assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+}
+
+func TestInvalidTrustedProxy(t *testing.T) {
+	_, err := NewHeaderPolicy(config.HeaderPolicyConfig{}, []string{"not-an-ip"})
+	assert.Error(t, err)
+}
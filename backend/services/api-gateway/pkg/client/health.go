@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var upstreamEndpointHealthy = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "gateway_upstream_endpoint_healthy",
+		Help: "Whether an upstream endpoint passes active health checks (1) or is ejected (0)",
+	},
+	[]string{"service", "endpoint"},
+)
+
+// healthChecker probes the endpoints of one service on an interval and ejects
+// endpoints that keep failing
+type healthChecker struct {
+	service   string
+	config    config.HealthCheckConfig
+	endpoints []*Endpoint
+	client    *http.Client
+	logger    *zap.Logger
+
+	// consecutive results per endpoint; positive counts successes, negative failures
+	streaks map[*Endpoint]int
+}
+
+func newHealthChecker(service string, cfg config.HealthCheckConfig, endpoints []*Endpoint, tlsConfig *tls.Config, logger *zap.Logger) *healthChecker {
+	if cfg.UnhealthyThreshold <= 0 {
+		cfg.UnhealthyThreshold = 1
+	}
+	if cfg.HealthyThreshold <= 0 {
+		cfg.HealthyThreshold = 1
+	}
+
+	for _, endpoint := range endpoints {
+		upstreamEndpointHealthy.WithLabelValues(service, endpoint.URL.Host).Set(1)
+	}
+
+	client := &http.Client{Timeout: cfg.Timeout}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client.Transport = transport
+	}
+
+	return &healthChecker{
+		service:   service,
+		config:    cfg,
+		endpoints: endpoints,
+		client:    client,
+		logger:    logger,
+		streaks:   make(map[*Endpoint]int),
+	}
+}
+
+// run probes all endpoints every interval until ctx is cancelled
+func (h *healthChecker) run(ctx context.Context) {
+	ticker := time.NewTicker(h.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		h.checkAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAll probes every endpoint concurrently and applies the results
+func (h *healthChecker) checkAll(ctx context.Context) {
+	results := make([]bool, len(h.endpoints))
+
+	var wg sync.WaitGroup
+	for i, endpoint := range h.endpoints {
+		wg.Add(1)
+		go func(i int, endpoint *Endpoint) {
+			defer wg.Done()
+			results[i] = h.probe(ctx, endpoint)
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return
+	}
+	for i, endpoint := range h.endpoints {
+		h.record(endpoint, results[i])
+	}
+}
+
+// probe returns true when the endpoint answers its health path with a 2xx
+func (h *healthChecker) probe(ctx context.Context, endpoint *Endpoint) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.URL.String()+h.config.Path, nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// record updates the streak of an endpoint and flips its state once a
+// threshold is crossed
+func (h *healthChecker) record(endpoint *Endpoint, ok bool) {
+	streak := h.streaks[endpoint]
+	switch {
+	case ok && streak >= 0:
+		streak++
+	case ok:
+		streak = 1
+	case streak <= 0:
+		streak--
+	default:
+		streak = -1
+	}
+	h.streaks[endpoint] = streak
+
+	if endpoint.Healthy() && -streak >= h.config.UnhealthyThreshold {
+		h.transition(endpoint, false)
+	} else if !endpoint.Healthy() && streak >= h.config.HealthyThreshold {
+		h.transition(endpoint, true)
+	}
+}
+
+func (h *healthChecker) transition(endpoint *Endpoint, healthy bool) {
+	if !endpoint.setHealthy(healthy) {
+		return
+	}
+
+	value := 0.0
+	if healthy {
+		value = 1
+		h.logger.Info("Upstream endpoint healthy",
+			zap.String("service", h.service),
+			zap.String("endpoint", endpoint.URL.Host),
+		)
+	} else {
+		h.logger.Warn("Upstream endpoint unhealthy, ejecting",
+			zap.String("service", h.service),
+			zap.String("endpoint", endpoint.URL.Host),
+			zap.Int("failures", h.config.UnhealthyThreshold),
+		)
+	}
+	upstreamEndpointHealthy.WithLabelValues(h.service, endpoint.URL.Host).Set(value)
+}
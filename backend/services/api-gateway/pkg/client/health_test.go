@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHealthChecker(t *testing.T) {
+	var failing int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" || atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	serviceConfig := testServiceConfig(t, upstream)
+	endpoint, err := newEndpoint(serviceConfig.ResolvedEndpoints()[0])
+	require.NoError(t, err)
+
+	checker := newHealthChecker("tenant-service", config.HealthCheckConfig{
+		Path:               "/health",
+		Timeout:            time.Second,
+		UnhealthyThreshold: 2,
+		HealthyThreshold:   2,
+	}, []*Endpoint{endpoint}, nil, zap.NewNop())
+	ctx := context.Background()
+
+	checker.checkAll(ctx)
+	assert.True(t, endpoint.Healthy())
+
+	atomic.StoreInt32(&failing, 1)
+	checker.checkAll(ctx)
+	assert.True(t, endpoint.Healthy(), "one failure is below the threshold")
+	checker.checkAll(ctx)
+	assert.False(t, endpoint.Healthy())
+
+	balancer, err := NewBalancer(RoundRobin, []*Endpoint{endpoint})
+	require.NoError(t, err)
+	assert.Nil(t, balancer.Next(""))
+
+	atomic.StoreInt32(&failing, 0)
+	checker.checkAll(ctx)
+	assert.False(t, endpoint.Healthy(), "one success is below the threshold")
+	checker.checkAll(ctx)
+	assert.True(t, endpoint.Healthy())
+	assert.Equal(t, endpoint, balancer.Next(""))
+}
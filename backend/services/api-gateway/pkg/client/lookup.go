@@ -0,0 +1,215 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"baribhara/api-gateway/internal/middleware"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// maxLookupBytes bounds the response a lookup will read
+const maxLookupBytes = 1 << 20
+
+// errLookupNotFound marks a lookup of a resource the service does not have
+var errLookupNotFound = errors.New("not found")
+
+// Lookup fetches a JSON document from a service for the gateway's own use,
+// with a GET over HTTP or a unary gRPC call. Calls go through the service's
+// balancer, retries, concurrency limit and circuit breaker.
+type Lookup struct {
+	service  string
+	upstream *upstream
+	client   *ServiceClient
+	timeout  time.Duration
+
+	// path is the HTTP path template; method is set instead for gRPC lookups
+	path   string
+	method *GRPCMethod
+}
+
+// NewHTTPLookup returns a lookup that GETs path, whose ":name" segments and
+// query values are filled in from the parameters given to Fetch
+func (m *Manager) NewHTTPLookup(service, path string) (*Lookup, error) {
+	if _, err := url.Parse(path); err != nil {
+		return nil, fmt.Errorf("invalid lookup path %q: %w", path, err)
+	}
+	return m.newLookup(service, path, nil)
+}
+
+// NewGRPCLookup returns a lookup that calls a unary gRPC method. params maps
+// the parameters given to Fetch to request fields.
+func (m *Manager) NewGRPCLookup(service, method string, params map[string]string) (*Lookup, error) {
+	grpcMethod, err := m.NewGRPCMethod(service, method, params)
+	if err != nil {
+		return nil, err
+	}
+	return m.newLookup(service, "", grpcMethod)
+}
+
+func (m *Manager) newLookup(service, path string, method *GRPCMethod) (*Lookup, error) {
+	upstream, ok := m.upstreams[service]
+	if !ok {
+		return nil, fmt.Errorf("unknown service %q", service)
+	}
+
+	return &Lookup{
+		service:  service,
+		upstream: upstream,
+		client:   m.GetClient(service),
+		timeout:  m.config.Services[service].Timeout,
+		path:     path,
+		method:   method,
+	}, nil
+}
+
+// Fetch calls the lookup with params and returns the decoded JSON response,
+// or nil when the service has no such resource. The Authorization,
+// X-Request-Id and X-User-* headers are passed on from header, so the service
+// answers on behalf of the caller.
+func (l *Lookup) Fetch(ctx context.Context, params map[string]string, header http.Header) (interface{}, error) {
+	if l.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+	}
+
+	var data []byte
+	var err error
+	_, refused := l.client.guard(ctx, func() bool {
+		if l.method != nil {
+			data, err = l.fetchGRPC(ctx, params, header)
+		} else {
+			data, err = l.fetchHTTP(ctx, params, header)
+		}
+		return upstreamFault(err)
+	})
+	if refused != nil {
+		return nil, fmt.Errorf("%s: %w", l.service, refused)
+	}
+	if errors.Is(err, errLookupNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", l.service, err)
+	}
+
+	var document interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("%s: invalid lookup response: %w", l.service, err)
+	}
+	return document, nil
+}
+
+func (l *Lookup) fetchHTTP(ctx context.Context, params map[string]string, header http.Header) ([]byte, error) {
+	target, _ := url.Parse(l.path)
+	segments := strings.Split(target.Path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = url.PathEscape(params[segment[1:]])
+		}
+	}
+	query := target.Query()
+	for name, values := range query {
+		for i, value := range values {
+			if strings.HasPrefix(value, ":") {
+				query[name][i] = params[value[1:]]
+			}
+		}
+	}
+
+	// Scheme and host are filled in per attempt by RoundTrip
+	ctx = withTarget(ctx, upstreamTarget{key: params["user_id"], retryable: true})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+l.service, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.Path = strings.Join(segments, "/")
+	req.URL.RawQuery = query.Encode()
+	req.Header.Set("Accept", "application/json")
+	for _, name := range forwardedMetadata {
+		if value := header.Get(name); value != "" {
+			req.Header.Set(name, value)
+		}
+	}
+	for name, values := range header {
+		if strings.HasPrefix(name, middleware.IdentityHeaderPrefix) {
+			req.Header[name] = values
+		}
+	}
+
+	resp, err := l.upstream.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errLookupNotFound
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, &lookupStatusError{status: resp.StatusCode}
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxLookupBytes))
+}
+
+func (l *Lookup) fetchGRPC(ctx context.Context, params map[string]string, header http.Header) ([]byte, error) {
+	m := l.method
+	req := m.input.New().Interface()
+	for param, field := range m.params {
+		if err := runtime.PopulateFieldFromPath(req, field, params[param]); err != nil {
+			return nil, fmt.Errorf("lookup parameter %s: %w", param, err)
+		}
+	}
+
+	ctx = metadata.NewOutgoingContext(ctx, outgoingMetadata(header))
+	resp := m.output.New().Interface()
+	if err := m.conn.Invoke(ctx, m.name, req, resp); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errLookupNotFound
+		}
+		return nil, err
+	}
+	return protojson.Marshal(resp)
+}
+
+// lookupStatusError is an HTTP lookup answered with an unexpected status
+type lookupStatusError struct {
+	status int
+}
+
+func (e *lookupStatusError) Error() string {
+	return fmt.Sprintf("lookup answered %d", e.status)
+}
+
+// upstreamFault reports whether an error calling the service counts against
+// its circuit breaker and concurrency limit: server errors and transport
+// errors do, client errors such as a refused token do not
+func upstreamFault(err error) bool {
+	if err == nil || errors.Is(err, errLookupNotFound) || errors.Is(err, errNoHealthyEndpoints) {
+		return false
+	}
+	var statusErr *lookupStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.status >= http.StatusInternalServerError
+	}
+	if st, ok := status.FromError(err); ok {
+		return runtime.HTTPStatusFromCode(st.Code()) >= http.StatusInternalServerError
+	}
+	return true
+}
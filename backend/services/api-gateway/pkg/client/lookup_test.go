@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+	"baribhara/api-gateway/pkg/pb/propertypb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func (testPropertyServer) GetPropertiesByCaretaker(ctx context.Context, req *propertypb.GetPropertiesByCaretakerRequest) (*propertypb.GetPropertiesByCaretakerResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get("x-user-id")) == 0 || md.Get("x-user-id")[0] != req.CaretakerId {
+		return &propertypb.GetPropertiesByCaretakerResponse{Success: false}, nil
+	}
+	return &propertypb.GetPropertiesByCaretakerResponse{
+		Success:    true,
+		Properties: []*propertypb.Property{{Id: "p-1"}, {Id: "p-2"}},
+	}, nil
+}
+
+func TestHTTPLookup(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/invoices/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"path":          r.URL.Path,
+			"query":         r.URL.RawQuery,
+			"authorization": r.Header.Get("Authorization"),
+			"user":          r.Header.Get("X-User-Id"),
+		})
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{}
+	cfg.Services = config.ServicesConfig{"invoice-service": testServiceConfig(t, upstream)}
+	manager, err := NewManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+	defer manager.Close()
+
+	lookup, err := manager.NewHTTPLookup("invoice-service", "/api/v1/invoices/:resource_id?tenant=:user_id")
+	require.NoError(t, err)
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer token")
+	header.Set("X-User-Id", "user 1")
+	header.Set("Cookie", "session=secret")
+
+	document, err := lookup.Fetch(context.Background(), map[string]string{"user_id": "user 1", "resource_id": "inv-1"}, header)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"path":          "/api/v1/invoices/inv-1",
+		"query":         "tenant=user+1",
+		"authorization": "Bearer token",
+		"user":          "user 1",
+	}, document)
+
+	// A missing resource is not an error
+	document, err = lookup.Fetch(context.Background(), map[string]string{"resource_id": "missing"}, header)
+	assert.NoError(t, err)
+	assert.Nil(t, document)
+}
+
+func TestGRPCLookup(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	propertypb.RegisterPropertyServiceServer(server, testPropertyServer{})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	cfg := &config.Config{}
+	cfg.Services = config.ServicesConfig{
+		"property-service": {Host: "127.0.0.1", Port: 1, GRPCPort: listener.Addr().(*net.TCPAddr).Port},
+	}
+	manager, err := NewManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+	defer manager.Close()
+
+	lookup, err := manager.NewGRPCLookup("property-service", "property.PropertyService/GetPropertiesByCaretaker",
+		map[string]string{"user_id": "caretakerId"})
+	require.NoError(t, err)
+
+	header := http.Header{}
+	header.Set("X-User-Id", "caretaker-1")
+	document, err := lookup.Fetch(context.Background(), map[string]string{"user_id": "caretaker-1"}, header)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"success":    true,
+		"properties": []interface{}{map[string]interface{}{"id": "p-1"}, map[string]interface{}{"id": "p-2"}},
+	}, document)
+}
+
+func TestLookupConcurrencyLimit(t *testing.T) {
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	service := testServiceConfig(t, upstream)
+	service.Concurrency = config.ConcurrencyConfig{
+		Enabled:          true,
+		InitialLimit:     1,
+		MinLimit:         1,
+		MaxLimit:         10,
+		Tolerance:        2,
+		Window:           time.Second,
+		LowPriorityShare: 0.5,
+	}
+	cfg := &config.Config{}
+	cfg.Services = config.ServicesConfig{"property-service": service}
+	manager, err := NewManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+	defer manager.Close()
+
+	lookup, err := manager.NewHTTPLookup("property-service", "/api/v1/properties")
+	require.NoError(t, err)
+
+	// Lookups share the service's limit with proxied requests
+	done := make(chan error)
+	go func() {
+		_, err := lookup.Fetch(context.Background(), nil, http.Header{})
+		done <- err
+	}()
+	limiter := manager.upstreams["property-service"].limiter
+	require.Eventually(t, func() bool {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return limiter.inFlight == 1
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = lookup.Fetch(context.Background(), nil, http.Header{})
+	assert.ErrorIs(t, err, errOverloaded)
+
+	close(release)
+	assert.NoError(t, <-done)
+	limiter.mu.Lock()
+	assert.Equal(t, 0, limiter.inFlight)
+	limiter.mu.Unlock()
+}
@@ -2,10 +2,14 @@ package client
 
 import (
 	"baribhara/api-gateway/internal/config"
+	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
-	"net/http/httputil"
-	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,130 +18,355 @@ import (
 
 // Manager manages HTTP clients for microservices
 type Manager struct {
-	clients map[string]*http.Client
-	config  *config.Config
-	logger  *zap.Logger
+	upstreams map[string]*upstream
+	config    *config.Config
+	logger    *zap.Logger
+	headers   *HeaderPolicy
+
+	// previous is the manager this one was reloaded from, until Activate
+	previous *Manager
+
+	breakersMu sync.Mutex
+	breakers   map[string]*CircuitBreaker
+
+	grpcMu    sync.Mutex
+	grpcPools map[string]*grpcPool
 }
 
 // NewManager creates a new client manager
 func NewManager(cfg *config.Config, logger *zap.Logger) (*Manager, error) {
+	return newManager(cfg, logger, nil)
+}
+
+// Reload creates a manager for cfg that carries over the connections, circuit
+// breakers, concurrency limits and endpoint health of every service whose
+// settings did not change. Call Activate on it once it replaces m.
+func (m *Manager) Reload(cfg *config.Config) (*Manager, error) {
+	return newManager(cfg, m.logger, m)
+}
+
+func newManager(cfg *config.Config, logger *zap.Logger, previous *Manager) (*Manager, error) {
 	manager := &Manager{
-		clients: make(map[string]*http.Client),
-		config:  cfg,
-		logger:  logger,
+		upstreams: make(map[string]*upstream),
+		config:    cfg,
+		logger:    logger,
+		previous:  previous,
+		breakers:  make(map[string]*CircuitBreaker),
+		grpcPools: make(map[string]*grpcPool),
+	}
+
+	headers, err := NewHeaderPolicy(cfg.Headers, cfg.Server.TrustedProxies)
+	if err != nil {
+		return nil, err
 	}
+	manager.headers = headers
 
 	// Initialize clients for each service
-	services := map[string]config.ServiceConfig{
-		"auth-service":      cfg.Services.AuthService,
-		"user-service":      cfg.Services.UserService,
-		"property-service":  cfg.Services.PropertyService,
-		"tenant-service":    cfg.Services.TenantService,
-		"invoice-service":   cfg.Services.InvoiceService,
-		"notification-service": cfg.Services.NotificationService,
-		"report-service":    cfg.Services.ReportService,
-		"admin-service":     cfg.Services.AdminService,
-		"caretaker-service": cfg.Services.CaretakerService,
-	}
-
-	for name, serviceConfig := range services {
-		client := &http.Client{
-			Timeout: 30 * time.Second,
+	for name, serviceConfig := range cfg.Services {
+		if upstream := previous.unchanged(name, serviceConfig); upstream != nil {
+			upstream.hold()
+			manager.upstreams[name] = upstream
+			if upstream.breaker != nil {
+				manager.breakers[name] = upstream.breaker
+			}
+			continue
+		}
+
+		stats := &connStats{service: name}
+		if previous != nil {
+			// The replaced upstream reports until Activate
+			stats.mute()
+		}
+		upstream, err := newUpstream(name, serviceConfig, stats, logger)
+		if err != nil {
+			manager.Close()
+			return nil, err
+		}
+		upstream.checkHealth(serviceConfig.HealthCheck)
+		manager.upstreams[name] = upstream
+
+		if serviceConfig.CircuitBreaker.Enabled {
+			upstream.breaker = manager.NewCircuitBreaker(name, serviceConfig.CircuitBreaker)
+		}
+		if serviceConfig.Concurrency.Enabled {
+			upstream.limiter = NewConcurrencyLimiter(name, serviceConfig.Concurrency)
 		}
-		manager.clients[name] = client
 	}
 
 	return manager, nil
 }
 
-// GetClient returns a client for the specified service
-func (m *Manager) GetClient(serviceName string) *ServiceClient {
-	client, exists := m.clients[serviceName]
-	if !exists {
+// unchanged returns the upstream of a service whose settings are the same in
+// cfg, or nil
+func (m *Manager) unchanged(name string, cfg config.ServiceConfig) *upstream {
+	if m == nil {
+		return nil
+	}
+	upstream, ok := m.upstreams[name]
+	if !ok || !reflect.DeepEqual(m.config.Services[name], cfg) {
 		return nil
 	}
+	return upstream
+}
 
-	serviceConfig := m.getServiceConfig(serviceName)
-	if serviceConfig == nil {
+// Activate hands the connection metrics over from the manager this one was
+// reloaded from, once it replaces it
+func (m *Manager) Activate() {
+	if m.previous == nil {
+		return
+	}
+	for name, upstream := range m.previous.upstreams {
+		if m.upstreams[name] != upstream {
+			upstream.stats.mute()
+		}
+	}
+	for _, upstream := range m.upstreams {
+		upstream.stats.unmute()
+	}
+	m.previous = nil
+}
+
+// Close stops the health checks of upstreams no other manager shares and
+// closes gRPC connections
+func (m *Manager) Close() {
+	for _, upstream := range m.upstreams {
+		upstream.drop()
+	}
+
+	m.grpcMu.Lock()
+	defer m.grpcMu.Unlock()
+	for _, pool := range m.grpcPools {
+		pool.Close()
+	}
+}
+
+// NewCircuitBreaker creates a breaker that is reported by CircuitStates. A
+// manager being reloaded keeps the breaker of the same name and settings.
+func (m *Manager) NewCircuitBreaker(name string, cfg config.CircuitBreakerConfig) *CircuitBreaker {
+	breaker := m.previous.breaker(name, cfg)
+	if breaker == nil {
+		breaker = NewCircuitBreaker(name, cfg)
+	}
+
+	m.breakersMu.Lock()
+	m.breakers[name] = breaker
+	m.breakersMu.Unlock()
+
+	return breaker
+}
+
+// breaker returns the breaker of the given name if it has the same settings
+func (m *Manager) breaker(name string, cfg config.CircuitBreakerConfig) *CircuitBreaker {
+	if m == nil {
 		return nil
 	}
+	m.breakersMu.Lock()
+	defer m.breakersMu.Unlock()
 
-	return &ServiceClient{
-		client: client,
-		config: *serviceConfig,
-		logger: m.logger,
-	}
-}
-
-// getServiceConfig returns the configuration for a service
-func (m *Manager) getServiceConfig(serviceName string) *config.ServiceConfig {
-	switch serviceName {
-	case "auth-service":
-		return &m.config.Services.AuthService
-	case "user-service":
-		return &m.config.Services.UserService
-	case "property-service":
-		return &m.config.Services.PropertyService
-	case "tenant-service":
-		return &m.config.Services.TenantService
-	case "invoice-service":
-		return &m.config.Services.InvoiceService
-	case "notification-service":
-		return &m.config.Services.NotificationService
-	case "report-service":
-		return &m.config.Services.ReportService
-	case "admin-service":
-		return &m.config.Services.AdminService
-	case "caretaker-service":
-		return &m.config.Services.CaretakerService
-	default:
+	breaker, ok := m.breakers[name]
+	if !ok || breaker.config != breakerDefaults(cfg) {
 		return nil
 	}
+	return breaker
+}
+
+// CircuitStates returns the current state of every circuit breaker by name
+func (m *Manager) CircuitStates() map[string]string {
+	m.breakersMu.Lock()
+	defer m.breakersMu.Unlock()
+
+	states := make(map[string]string, len(m.breakers))
+	for name, breaker := range m.breakers {
+		states[name] = breaker.State()
+	}
+	return states
+}
+
+// GetClient returns a client for the specified service
+func (m *Manager) GetClient(serviceName string) *ServiceClient {
+	upstream, exists := m.upstreams[serviceName]
+	if !exists {
+		return nil
+	}
+
+	return &ServiceClient{
+		upstream: upstream,
+		breaker:  upstream.breaker,
+		limiter:  upstream.limiter,
+		headers:  m.headers,
+		config:   m.config.Services[serviceName],
+		logger:   m.logger,
+	}
 }
 
 // ServiceClient represents a client for a specific service
 type ServiceClient struct {
-	client *http.Client
-	config config.ServiceConfig
-	logger *zap.Logger
+	upstream *upstream
+	breaker  *CircuitBreaker
+	limiter  *ConcurrencyLimiter
+	priority string
+	headers  *HeaderPolicy
+	config   config.ServiceConfig
+	logger   *zap.Logger
+}
+
+// Config returns the configuration of the service
+func (sc *ServiceClient) Config() config.ServiceConfig {
+	return sc.config
+}
+
+// WithCircuitBreaker returns a copy of the client guarded by the given breaker
+// instead of the service-wide one
+func (sc *ServiceClient) WithCircuitBreaker(breaker *CircuitBreaker) *ServiceClient {
+	clone := *sc
+	clone.breaker = breaker
+	return &clone
+}
+
+// WithPriority returns a copy of the client whose requests are shed according
+// to priority when the service is at its concurrency limit
+func (sc *ServiceClient) WithPriority(priority string) *ServiceClient {
+	clone := *sc
+	clone.priority = priority
+	return &clone
+}
+
+// WithRouteHeaders returns a copy of the client that applies the route's
+// header rules after the global header policy
+func (sc *ServiceClient) WithRouteHeaders(cfg *config.HeaderPolicyConfig) *ServiceClient {
+	clone := *sc
+	clone.headers = sc.headers.With(cfg)
+	return &clone
 }
 
 // ProxyRequest proxies a request to the service
 func (sc *ServiceClient) ProxyRequest(c *gin.Context, path string) {
-	// Build target URL
-	targetURL := fmt.Sprintf("http://%s:%d%s", sc.config.Host, sc.config.Port, path)
-	
-	target, err := url.Parse(targetURL)
-	if err != nil {
-		sc.logger.Error("Failed to parse target URL", zap.Error(err))
-		c.JSON(500, gin.H{"error": "Internal server error"})
-		return
+	sc.guardRequest(c, func() bool {
+		return sc.proxyRequest(c, path)
+	})
+}
+
+// Calls refused by guard
+var (
+	errOverloaded  = errors.New("service overloaded")
+	errCircuitOpen = errors.New("circuit open")
+)
+
+// guard calls the service within its concurrency limit and circuit breaker,
+// recording the outcome with both; call reports whether the service failed.
+// Calls shed or refused by the breaker never reach the service: guard returns
+// errOverloaded, or errCircuitOpen and how long the circuit stays open. Calls
+// the client gave up on or that ran out of the gateway's deadline are not held
+// against the service.
+func (sc *ServiceClient) guard(ctx context.Context, call func() bool) (time.Duration, error) {
+	if sc.limiter != nil {
+		if !sc.limiter.Acquire(sc.priority) {
+			return 0, errOverloaded
+		}
+	}
+	var generation uint64
+	if sc.breaker != nil {
+		allowed, wait, gen := sc.breaker.Allow()
+		if !allowed {
+			if sc.limiter != nil {
+				sc.limiter.Cancel()
+			}
+			return wait, errCircuitOpen
+		}
+		generation = gen
 	}
 
-	// Create reverse proxy
-	proxy := httputil.NewSingleHostReverseProxy(target)
-	
-	// Modify request
-	proxy.Director = func(req *http.Request) {
-		req.URL.Scheme = target.Scheme
-		req.URL.Host = target.Host
-		req.URL.Path = path
-		req.Host = target.Host
-		
-		// Copy headers
-		for key, values := range c.Request.Header {
-			for _, value := range values {
-				req.Header.Add(key, value)
+	start := time.Now()
+	failed := true
+	defer func() {
+		if ctx.Err() != nil {
+			// The client gave up or the route's deadline passed; the service
+			// is not to blame for the 502 or 504
+			if sc.breaker != nil {
+				sc.breaker.Cancel(generation)
 			}
+			if sc.limiter != nil {
+				sc.limiter.Cancel()
+			}
+			return
+		}
+
+		if sc.breaker != nil {
+			sc.breaker.Record(generation, !failed)
 		}
+		if sc.limiter != nil {
+			sc.limiter.Release(time.Since(start), failed)
+		}
+	}()
+
+	failed = call()
+	return 0, nil
+}
+
+// guardRequest guards a proxied request, answering it when guard refuses it
+func (sc *ServiceClient) guardRequest(c *gin.Context, call func() bool) {
+	wait, err := sc.guard(c.Request.Context(), call)
+	switch err {
+	case errOverloaded:
+		rejectOverloaded(c)
+	case errCircuitOpen:
+		rejectOpenCircuit(c, wait)
 	}
+}
+
+// proxyRequest proxies a request, reporting whether the service failed it
+func (sc *ServiceClient) proxyRequest(c *gin.Context, path string) bool {
+	failed := false
+	target := upstreamTarget{
+		path:    path,
+		key:     affinityKey(c),
+		headers: sc.headers,
+		failed:  &failed,
+	}
+
+	// Retried requests need a replayable body
+	retry := sc.upstream.retry
+	if retry.eligible(c.Request) {
+		target.retryable = bufferBody(c.Request, retry.config.MaxBodyBytes)
+	}
+
+	ctx := withTarget(c.Request.Context(), target)
+	sc.upstream.proxy.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+	return failed
+}
+
+// affinityKey returns the authenticated user id used for consistent hashing
+func affinityKey(c *gin.Context) string {
+	userID, exists := c.Get("user_id")
+	if !exists || userID == nil {
+		return ""
+	}
+	return fmt.Sprint(userID)
+}
+
+// rejectOverloaded sheds a request the service has no capacity for
+func rejectOverloaded(c *gin.Context) {
+	retryAfter := int(shedRetryAfter.Seconds())
+
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"error":       "Service overloaded, try again shortly",
+		"code":        "SERVICE_OVERLOADED",
+		"retry_after": retryAfter,
+	})
+}
 
-	// Handle errors
-	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
-		sc.logger.Error("Proxy error", zap.Error(err))
-		c.JSON(502, gin.H{"error": "Service unavailable"})
+// rejectOpenCircuit fails fast while a circuit breaker is open
+func rejectOpenCircuit(c *gin.Context, wait time.Duration) {
+	retryAfter := int(math.Ceil(wait.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
 	}
 
-	// Serve the request
-	proxy.ServeHTTP(c.Writer, c.Request)
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"error":       "Service temporarily unavailable",
+		"code":        "CIRCUIT_OPEN",
+		"retry_after": retryAfter,
+	})
 }
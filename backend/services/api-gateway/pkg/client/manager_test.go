@@ -0,0 +1,300 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// testServiceConfig points a service config at an httptest server
+func testServiceConfig(t *testing.T, server *httptest.Server) config.ServiceConfig {
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	host, portStr, err := net.SplitHostPort(u.Host)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	return config.ServiceConfig{
+		Host: host,
+		Port: port,
+		Transport: config.TransportConfig{
+			MaxIdleConns:        10,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     time.Minute,
+			DialTimeout:         time.Second,
+		},
+	}
+}
+
+func TestProxyRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	remotes := make(map[string]bool)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remotes[r.RemoteAddr] = true
+		w.Write([]byte(r.URL.Path + "?" + r.URL.RawQuery))
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{}
+	cfg.Services = config.ServicesConfig{"tenant-service": testServiceConfig(t, upstream)}
+	manager, err := NewManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/tenants/:id", func(c *gin.Context) {
+		manager.GetClient("tenant-service").ProxyRequest(c, "/api/v1/tenants/"+c.Param("id"))
+	})
+
+	gateway := httptest.NewServer(router)
+	defer gateway.Close()
+
+	for i := 0; i < 3; i++ {
+		resp, err := http.Get(gateway.URL + "/tenants/42?expand=lease")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "/api/v1/tenants/42?expand=lease", string(body))
+	}
+
+	// Sequential requests should reuse one pooled connection
+	assert.Len(t, remotes, 1)
+}
+
+func TestProxyRequestUpstreamDown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	cfg := &config.Config{}
+	cfg.Services = config.ServicesConfig{"invoice-service": testServiceConfig(t, upstream)}
+	upstream.Close()
+
+	manager, err := NewManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/invoices", func(c *gin.Context) {
+		manager.GetClient("invoice-service").ProxyRequest(c, "/api/v1/invoices")
+	})
+
+	gateway := httptest.NewServer(router)
+	defer gateway.Close()
+
+	resp, err := http.Get(gateway.URL + "/invoices")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Contains(t, string(body), "Service unavailable")
+}
+
+func TestProxyRequestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	forwarded := make(chan string, 1)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded <- r.Header.Get("X-Request-Timeout")
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{}
+	cfg.Services = config.ServicesConfig{"report-service": testServiceConfig(t, upstream)}
+	manager, err := NewManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/reports", func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 100*time.Millisecond)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		manager.GetClient("report-service").ProxyRequest(c, "/api/v1/reports/generate")
+	})
+	gateway := httptest.NewServer(router)
+	defer gateway.Close()
+
+	resp, err := http.Get(gateway.URL + "/reports")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	assert.Contains(t, string(body), "UPSTREAM_TIMEOUT")
+
+	header := <-forwarded
+	remaining, err := strconv.Atoi(header)
+	require.NoError(t, err)
+	assert.True(t, remaining > 0 && remaining <= 100, "forwarded %q", header)
+}
+
+func TestProxyRequestClientCancel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{}
+	cfg.Services = config.ServicesConfig{"report-service": testServiceConfig(t, upstream)}
+	manager, err := NewManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	breaker := NewCircuitBreaker("report-service", config.CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		CoolDown:            time.Minute,
+	})
+	client := manager.GetClient("report-service").WithCircuitBreaker(breaker)
+	done := make(chan struct{})
+	router := gin.New()
+	router.GET("/reports", func(c *gin.Context) {
+		defer close(done)
+		client.ProxyRequest(c, "/api/v1/reports/generate")
+	})
+	gateway := httptest.NewServer(router)
+	defer gateway.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", gateway.URL+"/reports", nil)
+	_, err = http.DefaultClient.Do(req)
+	require.Error(t, err)
+
+	<-done
+	assert.Equal(t, StateClosed, breaker.State(), "an abandoned request is not an upstream failure")
+}
+
+func TestProxyRequestFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		down     bool
+		deadline time.Duration
+		state    string
+	}{
+		{
+			name:    "Upstream server error",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			state:   StateOpen,
+		},
+		{
+			name:    "Upstream client error",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) },
+			state:   StateClosed,
+		},
+		{
+			name:  "Upstream unreachable",
+			down:  true,
+			state: StateOpen,
+		},
+		{
+			name:     "Gateway deadline",
+			handler:  func(w http.ResponseWriter, r *http.Request) { <-r.Context().Done() },
+			deadline: 50 * time.Millisecond,
+			state:    StateClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := httptest.NewServer(tt.handler)
+			defer upstream.Close()
+			cfg := &config.Config{}
+			cfg.Services = config.ServicesConfig{"report-service": testServiceConfig(t, upstream)}
+			if tt.down {
+				upstream.Close()
+			}
+			manager, err := NewManager(cfg, zap.NewNop())
+			require.NoError(t, err)
+			defer manager.Close()
+
+			breaker := NewCircuitBreaker("report-service", config.CircuitBreakerConfig{
+				ConsecutiveFailures: 1,
+				CoolDown:            time.Minute,
+			})
+			client := manager.GetClient("report-service").WithCircuitBreaker(breaker)
+			router := gin.New()
+			router.GET("/reports", func(c *gin.Context) {
+				if tt.deadline > 0 {
+					ctx, cancel := context.WithTimeout(c.Request.Context(), tt.deadline)
+					defer cancel()
+					c.Request = c.Request.WithContext(ctx)
+				}
+				client.ProxyRequest(c, "/api/v1/reports")
+			})
+
+			gateway := httptest.NewServer(router)
+			defer gateway.Close()
+
+			resp, err := http.Get(gateway.URL + "/reports")
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.state, breaker.State())
+		})
+	}
+}
+
+func TestManagerReload(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+
+	service := testServiceConfig(t, upstream)
+	service.CircuitBreaker = config.CircuitBreakerConfig{Enabled: true, ConsecutiveFailures: 1, CoolDown: time.Minute}
+	cfg := &config.Config{}
+	cfg.Services = config.ServicesConfig{"report-service": service, "tenant-service": service}
+	manager, err := NewManager(cfg, zap.NewNop())
+	require.NoError(t, err)
+	record(manager.GetClient("report-service").breaker, false)
+	record(manager.GetClient("tenant-service").breaker, false)
+	routeBreaker := manager.NewCircuitBreaker("report-service GET /reports", config.CircuitBreakerConfig{ConsecutiveFailures: 1})
+
+	changed := service
+	changed.Timeout = time.Second
+	next := &config.Config{}
+	next.Services = config.ServicesConfig{"report-service": service, "tenant-service": changed}
+	reloaded, err := manager.Reload(next)
+	require.NoError(t, err)
+	defer reloaded.Close()
+
+	// An unchanged service keeps its upstream, a changed one keeps its breaker
+	assert.Same(t, manager.upstreams["report-service"], reloaded.upstreams["report-service"])
+	assert.NotSame(t, manager.upstreams["tenant-service"], reloaded.upstreams["tenant-service"])
+	assert.Equal(t, map[string]string{"report-service": StateOpen, "tenant-service": StateOpen}, reloaded.CircuitStates())
+	assert.Same(t, routeBreaker, reloaded.NewCircuitBreaker("report-service GET /reports", config.CircuitBreakerConfig{ConsecutiveFailures: 1}))
+	assert.NotSame(t, routeBreaker, reloaded.NewCircuitBreaker("report-service GET /reports", config.CircuitBreakerConfig{ConsecutiveFailures: 2}))
+
+	// The replaced upstream stops reporting before the new one starts
+	replaced, replacement := manager.upstreams["tenant-service"], reloaded.upstreams["tenant-service"]
+	assert.False(t, replaced.stats.muted.Load())
+	assert.True(t, replacement.stats.muted.Load())
+	reloaded.Activate()
+	assert.True(t, replaced.stats.muted.Load())
+	assert.False(t, replacement.stats.muted.Load())
+
+	// Closing the old manager leaves the shared upstream running
+	manager.Close()
+	assert.Equal(t, int32(1), reloaded.upstreams["report-service"].refs.Load())
+	assert.Equal(t, int32(0), replaced.refs.Load())
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	upstreamRetries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gateway_upstream_retries_total",
+			Help: "Upstream requests retried, by service and reason",
+		},
+		[]string{"service", "reason"},
+	)
+
+	upstreamRetryBudgetExhausted = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gateway_upstream_retry_budget_exhausted_total",
+			Help: "Retries skipped because the service's retry budget was spent",
+		},
+		[]string{"service"},
+	)
+)
+
+// retryPolicy decides which requests and failures are retried for a service
+type retryPolicy struct {
+	config   config.RetryConfig
+	methods  map[string]bool
+	statuses map[int]bool
+	budget   *retryBudget
+}
+
+// newRetryPolicy returns nil when retries are disabled for the service
+func newRetryPolicy(cfg config.RetryConfig) *retryPolicy {
+	if !cfg.Enabled || cfg.MaxAttempts <= 1 {
+		return nil
+	}
+
+	policy := &retryPolicy{
+		config:   cfg,
+		methods:  make(map[string]bool),
+		statuses: make(map[int]bool),
+		budget:   newRetryBudget(cfg.BudgetRatio, cfg.BudgetMinRetries, cfg.BudgetWindow),
+	}
+	for _, method := range cfg.Methods {
+		policy.methods[strings.ToUpper(method)] = true
+	}
+	for _, status := range cfg.RetryOn {
+		policy.statuses[status] = true
+	}
+
+	return policy
+}
+
+// eligible reports whether the request may be retried. POST is only retried
+// when the client sent an Idempotency-Key.
+func (p *retryPolicy) eligible(req *http.Request) bool {
+	if p == nil {
+		return false
+	}
+	if p.methods[req.Method] {
+		return true
+	}
+	return req.Method == http.MethodPost && req.Header.Get("Idempotency-Key") != ""
+}
+
+// reason returns why an attempt should be retried, or "" if it should not
+func (p *retryPolicy) reason(ctx context.Context, resp *http.Response, err error) string {
+	if ctx.Err() != nil {
+		return ""
+	}
+	if err != nil {
+		return "connect_error"
+	}
+	if p.statuses[resp.StatusCode] {
+		return "status_" + strconv.Itoa(resp.StatusCode)
+	}
+	return ""
+}
+
+// wait sleeps for the backoff of the given attempt, using full jitter
+func (p *retryPolicy) wait(ctx context.Context, attempt int) error {
+	backoff := p.config.InitialBackoff << (attempt - 1)
+	if backoff <= 0 || backoff > p.config.MaxBackoff {
+		backoff = p.config.MaxBackoff
+	}
+	if backoff <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(backoff)) + 1))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// bufferBody reads the request body into memory so it can be replayed. It
+// returns false, leaving the body streamable, when it exceeds maxBytes.
+func bufferBody(req *http.Request, maxBytes int64) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(req.Body, maxBytes+1))
+	if err != nil {
+		req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(buf), req.Body), Closer: req.Body}
+		return false
+	}
+	if int64(len(buf)) > maxBytes {
+		req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(buf), req.Body), Closer: req.Body}
+		return false
+	}
+
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(buf))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf)), nil
+	}
+	return true
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// retryBudget caps retries to a ratio of recent requests, plus a small floor,
+// so retries cannot multiply the load on a service that is already failing
+type retryBudget struct {
+	ratio      float64
+	minRetries int
+	window     time.Duration
+	now        func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	requests    int
+	retries     int
+}
+
+func newRetryBudget(ratio float64, minRetries int, window time.Duration) *retryBudget {
+	if window <= 0 {
+		window = 10 * time.Second
+	}
+	return &retryBudget{
+		ratio:       ratio,
+		minRetries:  minRetries,
+		window:      window,
+		now:         time.Now,
+		windowStart: time.Now(),
+	}
+}
+
+// deposit records an original (non-retry) request
+func (b *retryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.roll()
+	b.requests++
+}
+
+// withdraw spends one retry, returning false when the budget is exhausted
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.roll()
+	if float64(b.retries) >= float64(b.minRetries)+b.ratio*float64(b.requests) {
+		return false
+	}
+	b.retries++
+	return true
+}
+
+func (b *retryBudget) roll() {
+	if now := b.now(); now.Sub(b.windowStart) >= b.window {
+		b.windowStart = now
+		b.requests = 0
+		b.retries = 0
+	}
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func testRetryConfig() config.RetryConfig {
+	return config.RetryConfig{
+		Enabled:          true,
+		MaxAttempts:      3,
+		InitialBackoff:   time.Millisecond,
+		MaxBackoff:       5 * time.Millisecond,
+		RetryOn:          []int{502, 503, 504},
+		Methods:          []string{"GET", "PUT"},
+		MaxBodyBytes:     1024,
+		BudgetRatio:      0.2,
+		BudgetMinRetries: 10,
+		BudgetWindow:     time.Minute,
+	}
+}
+
+// flakyUpstream fails the first n requests with 503 and echoes the body after
+func flakyUpstream(n int32) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= n {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	return server, &calls
+}
+
+func TestRetryPolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		method         string
+		idempotencyKey string
+		failures       int32
+		expectedStatus int
+		expectedCalls  int32
+	}{
+		{
+			name:           "GET is retried",
+			method:         "GET",
+			failures:       2,
+			expectedStatus: http.StatusOK,
+			expectedCalls:  3,
+		},
+		{
+			name:           "PUT body is replayed",
+			method:         "PUT",
+			failures:       1,
+			expectedStatus: http.StatusOK,
+			expectedCalls:  2,
+		},
+		{
+			name:           "Attempts are capped",
+			method:         "GET",
+			failures:       5,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCalls:  3,
+		},
+		{
+			name:           "POST without Idempotency-Key is not retried",
+			method:         "POST",
+			failures:       1,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCalls:  1,
+		},
+		{
+			name:           "POST with Idempotency-Key is retried",
+			method:         "POST",
+			idempotencyKey: "pay-123",
+			failures:       1,
+			expectedStatus: http.StatusOK,
+			expectedCalls:  2,
+		},
+		{
+			name:           "PATCH is not retried even with Idempotency-Key",
+			method:         "PATCH",
+			idempotencyKey: "pay-123",
+			failures:       1,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream, calls := flakyUpstream(tt.failures)
+			defer upstream.Close()
+
+			cfg := &config.Config{}
+			serviceConfig := testServiceConfig(t, upstream)
+			serviceConfig.Retry = testRetryConfig()
+			cfg.Services = config.ServicesConfig{"invoice-service": serviceConfig}
+			manager, err := NewManager(cfg, zap.NewNop())
+			require.NoError(t, err)
+
+			router := gin.New()
+			router.Any("/invoices", func(c *gin.Context) {
+				manager.GetClient("invoice-service").ProxyRequest(c, "/api/v1/invoices")
+			})
+			gateway := httptest.NewServer(router)
+			defer gateway.Close()
+
+			req, _ := http.NewRequest(tt.method, gateway.URL+"/invoices", strings.NewReader(`{"amount":1500}`))
+			if tt.idempotencyKey != "" {
+				req.Header.Set("Idempotency-Key", tt.idempotencyKey)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedCalls, atomic.LoadInt32(calls))
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, `{"amount":1500}`, string(body))
+			}
+		})
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	budget := newRetryBudget(0.5, 1, time.Minute)
+
+	for i := 0; i < 4; i++ {
+		budget.deposit()
+	}
+
+	// One retry from the floor plus half of the four requests
+	assert.True(t, budget.withdraw())
+	assert.True(t, budget.withdraw())
+	assert.True(t, budget.withdraw())
+	assert.False(t, budget.withdraw())
+}
@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	upstreamConnections = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gateway_upstream_connections",
+			Help: "Number of open connections to a service's upstreams",
+		},
+		[]string{"service"},
+	)
+
+	upstreamInFlight = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gateway_upstream_requests_in_flight",
+			Help: "Number of requests to a service's upstreams awaiting or streaming a response",
+		},
+		[]string{"service"},
+	)
+)
+
+// newTLSConfig builds the client TLS settings for a service, or nil when the
+// service is reached over plain HTTP
+func newTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// newTransport builds the pooled transport used for every request to a
+// service, counting its connections and requests in stats
+func newTransport(stats *connStats, cfg config.TransportConfig, tlsConfig *tls.Config) http.RoundTripper {
+	dialer := &net.Dialer{
+		Timeout:   cfg.DialTimeout,
+		KeepAlive: cfg.KeepAlive,
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			stats.opened()
+			return &trackedConn{Conn: conn, stats: stats}, nil
+		},
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+	}
+
+	stats.publish()
+	return &trackedTransport{transport: transport, stats: stats}
+}
+
+// connStats tracks open connections and in-flight requests for one service.
+// The two are not comparable: HTTP/2 multiplexes many requests on a connection.
+// Muted stats keep counting but leave the gauges to the service's upstream
+// that replaced them on reload.
+type connStats struct {
+	service  string
+	open     int64
+	inFlight int64
+	muted    atomic.Bool
+}
+
+func (s *connStats) mute() {
+	s.muted.Store(true)
+}
+
+func (s *connStats) unmute() {
+	s.muted.Store(false)
+	s.publish()
+}
+
+func (s *connStats) opened() {
+	atomic.AddInt64(&s.open, 1)
+	s.publish()
+}
+
+func (s *connStats) closed() {
+	atomic.AddInt64(&s.open, -1)
+	s.publish()
+}
+
+func (s *connStats) acquire() {
+	atomic.AddInt64(&s.inFlight, 1)
+	s.publish()
+}
+
+func (s *connStats) release() {
+	atomic.AddInt64(&s.inFlight, -1)
+	s.publish()
+}
+
+// publish exports the current counts
+func (s *connStats) publish() {
+	if s.muted.Load() {
+		return
+	}
+	upstreamConnections.WithLabelValues(s.service).Set(float64(atomic.LoadInt64(&s.open)))
+	upstreamInFlight.WithLabelValues(s.service).Set(float64(atomic.LoadInt64(&s.inFlight)))
+}
+
+// trackedTransport counts a request as in flight for the lifetime of its response
+type trackedTransport struct {
+	transport *http.Transport
+	stats     *connStats
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *trackedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.stats.acquire()
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		t.stats.release()
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusSwitchingProtocols {
+		// Upgraded connections leave the pool; the proxy needs the raw body
+		t.stats.release()
+		return resp, nil
+	}
+	resp.Body = &trackedBody{ReadCloser: resp.Body, release: t.stats.release}
+	return resp, nil
+}
+
+// trackedBody ends its request's time in flight when the body is closed
+type trackedBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *trackedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// trackedConn decrements the open connection count when closed
+type trackedConn struct {
+	net.Conn
+	once  sync.Once
+	stats *connStats
+}
+
+func (c *trackedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.stats.closed)
+	return err
+}
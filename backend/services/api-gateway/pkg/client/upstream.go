@@ -0,0 +1,306 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"baribhara/api-gateway/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// errNoHealthyEndpoints is returned when every endpoint of a service is ejected
+var errNoHealthyEndpoints = errors.New("no healthy endpoints")
+
+// deadlineHeader tells upstreams how many milliseconds are left before the
+// gateway gives up on the request
+const deadlineHeader = "X-Request-Timeout"
+
+// upstreamTargetKey carries the per-request routing data through the shared proxy
+type upstreamTargetKey struct{}
+
+type upstreamTarget struct {
+	path      string
+	key       string
+	retryable bool
+	headers   *HeaderPolicy
+
+	// failed is set when the service failed the request: it answered with a
+	// server error or could not be reached
+	failed *bool
+}
+
+// upstream holds the long-lived proxy and balancer of one service. It is also
+// the proxy's transport: every attempt picks an endpoint from the balancer, so
+// a retry can land on a different instance. Managers reloaded with the same
+// service settings share the upstream, and with it its state.
+type upstream struct {
+	name      string
+	proxy     *httputil.ReverseProxy
+	balancer  Balancer
+	endpoints []*Endpoint
+	breaker   *CircuitBreaker
+	limiter   *ConcurrencyLimiter
+	retry     *retryPolicy
+	transport http.RoundTripper
+	stats     *connStats
+	tls       *tls.Config
+	logger    *zap.Logger
+
+	// refs counts the managers sharing the upstream
+	refs       atomic.Int32
+	stopHealth context.CancelFunc
+}
+
+// newUpstream builds the endpoints, balancer and reverse proxy for a service,
+// held by one manager
+func newUpstream(name string, serviceConfig config.ServiceConfig, stats *connStats, logger *zap.Logger) (*upstream, error) {
+	tlsConfig, err := newTLSConfig(serviceConfig.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS settings for %s: %w", name, err)
+	}
+
+	var endpoints []*Endpoint
+	for _, endpointConfig := range serviceConfig.ResolvedEndpoints() {
+		endpoint, err := newEndpoint(endpointConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint for %s: %w", name, err)
+		}
+		if tlsConfig != nil {
+			endpoint.URL.Scheme = "https"
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	balancer, err := NewBalancer(serviceConfig.Balancer, endpoints)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	u := &upstream{
+		name:      name,
+		balancer:  balancer,
+		endpoints: endpoints,
+		retry:     newRetryPolicy(serviceConfig.Retry),
+		transport: newTransport(stats, serviceConfig.Transport, tlsConfig),
+		stats:     stats,
+		tls:       tlsConfig,
+		logger:    logger,
+	}
+	u.refs.Store(1)
+
+	u.proxy = &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			target, ok := req.Context().Value(upstreamTargetKey{}).(upstreamTarget)
+			if !ok {
+				return
+			}
+			// Scheme and host are filled in per attempt by RoundTrip
+			req.URL.Scheme = "http"
+			req.URL.Path = target.path
+			req.URL.RawPath = ""
+			if target.headers != nil {
+				target.headers.ApplyRequest(req)
+			}
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			target, ok := resp.Request.Context().Value(upstreamTargetKey{}).(upstreamTarget)
+			if ok && target.headers != nil {
+				target.headers.ApplyResponse(resp)
+			}
+			if ok && target.failed != nil {
+				*target.failed = resp.StatusCode >= http.StatusInternalServerError
+			}
+			return nil
+		},
+		Transport: u,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			logger.Error("Proxy error", zap.String("service", name), zap.Error(err))
+			if target, ok := r.Context().Value(upstreamTargetKey{}).(upstreamTarget); ok && target.failed != nil {
+				*target.failed = upstreamFault(err)
+			}
+
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			switch {
+			case isTimeout(err):
+				w.WriteHeader(http.StatusGatewayTimeout)
+				json.NewEncoder(w).Encode(gin.H{
+					"error": "Upstream request timed out",
+					"code":  "UPSTREAM_TIMEOUT",
+				})
+			case errors.Is(err, errNoHealthyEndpoints):
+				w.WriteHeader(http.StatusServiceUnavailable)
+				json.NewEncoder(w).Encode(gin.H{"error": "Service unavailable"})
+			default:
+				w.WriteHeader(http.StatusBadGateway)
+				json.NewEncoder(w).Encode(gin.H{"error": "Service unavailable"})
+			}
+		},
+	}
+
+	return u, nil
+}
+
+// checkHealth starts active health checks of the endpoints, which run until
+// the last manager drops the upstream
+func (u *upstream) checkHealth(cfg config.HealthCheckConfig) {
+	ctx, cancel := context.WithCancel(context.Background())
+	u.stopHealth = cancel
+	if !cfg.Enabled || cfg.Interval <= 0 {
+		return
+	}
+	checker := newHealthChecker(u.name, cfg, u.endpoints, u.tls, u.logger)
+	go checker.run(ctx)
+}
+
+// hold shares the upstream with another manager
+func (u *upstream) hold() {
+	u.refs.Add(1)
+}
+
+// drop releases a manager's hold; the last one stops the health checks and
+// leaves the metrics to the service's next upstream
+func (u *upstream) drop() {
+	if u.refs.Add(-1) > 0 {
+		return
+	}
+	if u.stopHealth != nil {
+		u.stopHealth()
+	}
+	u.stats.mute()
+}
+
+// RoundTrip sends the request to a balanced endpoint, retrying according to the
+// service's retry policy
+func (u *upstream) RoundTrip(req *http.Request) (*http.Response, error) {
+	target, _ := req.Context().Value(upstreamTargetKey{}).(upstreamTarget)
+	retryable := target.retryable && u.retry != nil
+
+	if retryable {
+		u.retry.budget.deposit()
+	}
+
+	for attempt := 1; ; attempt++ {
+		endpoint := u.balancer.Next(target.key)
+		if endpoint == nil {
+			return nil, errNoHealthyEndpoints
+		}
+
+		out, err := u.prepareAttempt(req, endpoint, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoint.acquire()
+		resp, err := u.transport.RoundTrip(out)
+
+		reason := ""
+		if retryable && attempt < u.retry.config.MaxAttempts {
+			reason = u.retry.reason(req.Context(), resp, err)
+		}
+		if reason != "" && !u.retry.budget.withdraw() {
+			upstreamRetryBudgetExhausted.WithLabelValues(u.name).Inc()
+			reason = ""
+		}
+
+		if reason == "" {
+			if err != nil {
+				endpoint.release()
+				return nil, fmt.Errorf("%s: %w", endpoint.URL.Host, err)
+			}
+			if resp.StatusCode == http.StatusSwitchingProtocols {
+				// The proxy needs the raw upgraded body; the slot is freed now
+				endpoint.release()
+				return resp, nil
+			}
+			resp.Body = &endpointBody{ReadCloser: resp.Body, release: endpoint.release}
+			return resp, nil
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+		endpoint.release()
+
+		upstreamRetries.WithLabelValues(u.name, reason).Inc()
+		u.logger.Debug("Retrying upstream request",
+			zap.String("service", u.name),
+			zap.String("endpoint", endpoint.URL.Host),
+			zap.String("reason", reason),
+			zap.Int("attempt", attempt),
+		)
+
+		if err := u.retry.wait(req.Context(), attempt); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// prepareAttempt points a copy of the request at an endpoint, rewinding the
+// buffered body for every attempt after the first
+func (u *upstream) prepareAttempt(req *http.Request, endpoint *Endpoint, attempt int) (*http.Request, error) {
+	out := req.Clone(req.Context())
+	if attempt > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		out.Body = body
+	}
+
+	out.URL.Scheme = endpoint.URL.Scheme
+	out.URL.Host = endpoint.URL.Host
+	out.Host = endpoint.URL.Host
+
+	// Each attempt tells the upstream what is left of the deadline
+	if deadline, ok := req.Context().Deadline(); ok {
+		remaining := time.Until(deadline).Milliseconds()
+		if remaining <= 0 {
+			return nil, context.DeadlineExceeded
+		}
+		out.Header.Set(deadlineHeader, strconv.FormatInt(remaining, 10))
+	}
+
+	return out, nil
+}
+
+// isTimeout reports whether a proxy error means the deadline or an upstream
+// timeout was hit
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// withTarget attaches the routing data for the proxy to the request context
+func withTarget(ctx context.Context, target upstreamTarget) context.Context {
+	return context.WithValue(ctx, upstreamTargetKey{}, target)
+}
+
+// endpointBody releases the endpoint's in-flight slot once the response is read
+type endpointBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *endpointBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
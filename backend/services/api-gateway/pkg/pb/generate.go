@@ -0,0 +1,6 @@
+// Package pb holds the Go code generated from the shared proto definitions in
+// backend/shared/proto. Run go generate in this directory after changing them;
+// it needs protoc, protoc-gen-go and protoc-gen-go-grpc on PATH.
+package pb
+
+//go:generate protoc -I ../../../../shared/proto --go_out=../.. --go_opt=module=baribhara/api-gateway --go_opt=Mauth.proto=baribhara/api-gateway/pkg/pb/authpb --go_opt=Mproperty.proto=baribhara/api-gateway/pkg/pb/propertypb --go-grpc_out=../.. --go-grpc_opt=module=baribhara/api-gateway --go-grpc_opt=Mauth.proto=baribhara/api-gateway/pkg/pb/authpb --go-grpc_opt=Mproperty.proto=baribhara/api-gateway/pkg/pb/propertypb auth.proto property.proto
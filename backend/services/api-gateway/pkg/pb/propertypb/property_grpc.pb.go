@@ -0,0 +1,331 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: property.proto
+
+package propertypb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PropertyService_CreateProperty_FullMethodName           = "/property.PropertyService/CreateProperty"
+	PropertyService_GetProperty_FullMethodName              = "/property.PropertyService/GetProperty"
+	PropertyService_UpdateProperty_FullMethodName           = "/property.PropertyService/UpdateProperty"
+	PropertyService_DeleteProperty_FullMethodName           = "/property.PropertyService/DeleteProperty"
+	PropertyService_ListProperties_FullMethodName           = "/property.PropertyService/ListProperties"
+	PropertyService_SearchProperties_FullMethodName         = "/property.PropertyService/SearchProperties"
+	PropertyService_GetPropertiesByCaretaker_FullMethodName = "/property.PropertyService/GetPropertiesByCaretaker"
+)
+
+// PropertyServiceClient is the client API for PropertyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PropertyServiceClient interface {
+	CreateProperty(ctx context.Context, in *CreatePropertyRequest, opts ...grpc.CallOption) (*CreatePropertyResponse, error)
+	GetProperty(ctx context.Context, in *GetPropertyRequest, opts ...grpc.CallOption) (*GetPropertyResponse, error)
+	UpdateProperty(ctx context.Context, in *UpdatePropertyRequest, opts ...grpc.CallOption) (*UpdatePropertyResponse, error)
+	DeleteProperty(ctx context.Context, in *DeletePropertyRequest, opts ...grpc.CallOption) (*DeletePropertyResponse, error)
+	ListProperties(ctx context.Context, in *ListPropertiesRequest, opts ...grpc.CallOption) (*ListPropertiesResponse, error)
+	SearchProperties(ctx context.Context, in *SearchPropertiesRequest, opts ...grpc.CallOption) (*SearchPropertiesResponse, error)
+	GetPropertiesByCaretaker(ctx context.Context, in *GetPropertiesByCaretakerRequest, opts ...grpc.CallOption) (*GetPropertiesByCaretakerResponse, error)
+}
+
+type propertyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPropertyServiceClient(cc grpc.ClientConnInterface) PropertyServiceClient {
+	return &propertyServiceClient{cc}
+}
+
+func (c *propertyServiceClient) CreateProperty(ctx context.Context, in *CreatePropertyRequest, opts ...grpc.CallOption) (*CreatePropertyResponse, error) {
+	out := new(CreatePropertyResponse)
+	err := c.cc.Invoke(ctx, PropertyService_CreateProperty_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *propertyServiceClient) GetProperty(ctx context.Context, in *GetPropertyRequest, opts ...grpc.CallOption) (*GetPropertyResponse, error) {
+	out := new(GetPropertyResponse)
+	err := c.cc.Invoke(ctx, PropertyService_GetProperty_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *propertyServiceClient) UpdateProperty(ctx context.Context, in *UpdatePropertyRequest, opts ...grpc.CallOption) (*UpdatePropertyResponse, error) {
+	out := new(UpdatePropertyResponse)
+	err := c.cc.Invoke(ctx, PropertyService_UpdateProperty_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *propertyServiceClient) DeleteProperty(ctx context.Context, in *DeletePropertyRequest, opts ...grpc.CallOption) (*DeletePropertyResponse, error) {
+	out := new(DeletePropertyResponse)
+	err := c.cc.Invoke(ctx, PropertyService_DeleteProperty_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *propertyServiceClient) ListProperties(ctx context.Context, in *ListPropertiesRequest, opts ...grpc.CallOption) (*ListPropertiesResponse, error) {
+	out := new(ListPropertiesResponse)
+	err := c.cc.Invoke(ctx, PropertyService_ListProperties_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *propertyServiceClient) SearchProperties(ctx context.Context, in *SearchPropertiesRequest, opts ...grpc.CallOption) (*SearchPropertiesResponse, error) {
+	out := new(SearchPropertiesResponse)
+	err := c.cc.Invoke(ctx, PropertyService_SearchProperties_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *propertyServiceClient) GetPropertiesByCaretaker(ctx context.Context, in *GetPropertiesByCaretakerRequest, opts ...grpc.CallOption) (*GetPropertiesByCaretakerResponse, error) {
+	out := new(GetPropertiesByCaretakerResponse)
+	err := c.cc.Invoke(ctx, PropertyService_GetPropertiesByCaretaker_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PropertyServiceServer is the server API for PropertyService service.
+// All implementations must embed UnimplementedPropertyServiceServer
+// for forward compatibility
+type PropertyServiceServer interface {
+	CreateProperty(context.Context, *CreatePropertyRequest) (*CreatePropertyResponse, error)
+	GetProperty(context.Context, *GetPropertyRequest) (*GetPropertyResponse, error)
+	UpdateProperty(context.Context, *UpdatePropertyRequest) (*UpdatePropertyResponse, error)
+	DeleteProperty(context.Context, *DeletePropertyRequest) (*DeletePropertyResponse, error)
+	ListProperties(context.Context, *ListPropertiesRequest) (*ListPropertiesResponse, error)
+	SearchProperties(context.Context, *SearchPropertiesRequest) (*SearchPropertiesResponse, error)
+	GetPropertiesByCaretaker(context.Context, *GetPropertiesByCaretakerRequest) (*GetPropertiesByCaretakerResponse, error)
+	mustEmbedUnimplementedPropertyServiceServer()
+}
+
+// UnimplementedPropertyServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPropertyServiceServer struct {
+}
+
+func (UnimplementedPropertyServiceServer) CreateProperty(context.Context, *CreatePropertyRequest) (*CreatePropertyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProperty not implemented")
+}
+func (UnimplementedPropertyServiceServer) GetProperty(context.Context, *GetPropertyRequest) (*GetPropertyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProperty not implemented")
+}
+func (UnimplementedPropertyServiceServer) UpdateProperty(context.Context, *UpdatePropertyRequest) (*UpdatePropertyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProperty not implemented")
+}
+func (UnimplementedPropertyServiceServer) DeleteProperty(context.Context, *DeletePropertyRequest) (*DeletePropertyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProperty not implemented")
+}
+func (UnimplementedPropertyServiceServer) ListProperties(context.Context, *ListPropertiesRequest) (*ListPropertiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProperties not implemented")
+}
+func (UnimplementedPropertyServiceServer) SearchProperties(context.Context, *SearchPropertiesRequest) (*SearchPropertiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchProperties not implemented")
+}
+func (UnimplementedPropertyServiceServer) GetPropertiesByCaretaker(context.Context, *GetPropertiesByCaretakerRequest) (*GetPropertiesByCaretakerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPropertiesByCaretaker not implemented")
+}
+func (UnimplementedPropertyServiceServer) mustEmbedUnimplementedPropertyServiceServer() {}
+
+// UnsafePropertyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PropertyServiceServer will
+// result in compilation errors.
+type UnsafePropertyServiceServer interface {
+	mustEmbedUnimplementedPropertyServiceServer()
+}
+
+func RegisterPropertyServiceServer(s grpc.ServiceRegistrar, srv PropertyServiceServer) {
+	s.RegisterService(&PropertyService_ServiceDesc, srv)
+}
+
+func _PropertyService_CreateProperty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePropertyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PropertyServiceServer).CreateProperty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PropertyService_CreateProperty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PropertyServiceServer).CreateProperty(ctx, req.(*CreatePropertyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PropertyService_GetProperty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPropertyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PropertyServiceServer).GetProperty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PropertyService_GetProperty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PropertyServiceServer).GetProperty(ctx, req.(*GetPropertyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PropertyService_UpdateProperty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePropertyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PropertyServiceServer).UpdateProperty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PropertyService_UpdateProperty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PropertyServiceServer).UpdateProperty(ctx, req.(*UpdatePropertyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PropertyService_DeleteProperty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePropertyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PropertyServiceServer).DeleteProperty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PropertyService_DeleteProperty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PropertyServiceServer).DeleteProperty(ctx, req.(*DeletePropertyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PropertyService_ListProperties_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPropertiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PropertyServiceServer).ListProperties(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PropertyService_ListProperties_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PropertyServiceServer).ListProperties(ctx, req.(*ListPropertiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PropertyService_SearchProperties_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchPropertiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PropertyServiceServer).SearchProperties(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PropertyService_SearchProperties_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PropertyServiceServer).SearchProperties(ctx, req.(*SearchPropertiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PropertyService_GetPropertiesByCaretaker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPropertiesByCaretakerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PropertyServiceServer).GetPropertiesByCaretaker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PropertyService_GetPropertiesByCaretaker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PropertyServiceServer).GetPropertiesByCaretaker(ctx, req.(*GetPropertiesByCaretakerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PropertyService_ServiceDesc is the grpc.ServiceDesc for PropertyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PropertyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "property.PropertyService",
+	HandlerType: (*PropertyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateProperty",
+			Handler:    _PropertyService_CreateProperty_Handler,
+		},
+		{
+			MethodName: "GetProperty",
+			Handler:    _PropertyService_GetProperty_Handler,
+		},
+		{
+			MethodName: "UpdateProperty",
+			Handler:    _PropertyService_UpdateProperty_Handler,
+		},
+		{
+			MethodName: "DeleteProperty",
+			Handler:    _PropertyService_DeleteProperty_Handler,
+		},
+		{
+			MethodName: "ListProperties",
+			Handler:    _PropertyService_ListProperties_Handler,
+		},
+		{
+			MethodName: "SearchProperties",
+			Handler:    _PropertyService_SearchProperties_Handler,
+		},
+		{
+			MethodName: "GetPropertiesByCaretaker",
+			Handler:    _PropertyService_GetPropertiesByCaretaker_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "property.proto",
+}